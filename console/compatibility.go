@@ -0,0 +1,114 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+
+	"github.com/blang/semver/v4"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// CompatibilityConfigMapName is the optional configmap in the operator namespace overriding the compatibility matrix
+	CompatibilityConfigMapName = "odf-console-compatibility"
+	// CompatibilityConfigMapKey is the key of the compatibility matrix in the configmap
+	CompatibilityConfigMapKey = "compatibility.yaml"
+	// FeaturesAnnotationKey lists the console features of the chosen compatibility entry on the ConsolePlugin
+	FeaturesAnnotationKey = "odf.openshift.io/console-features"
+)
+
+//go:embed compatibility.yaml
+var defaultCompatibilityMatrix []byte
+
+// CompatibilityEntry maps a range of OpenShift versions to the plugin base path and the console features
+type CompatibilityEntry struct {
+	OCPVersions string   `json:"ocpVersions"`
+	BasePath    string   `json:"basePath"`
+	Features    []string `json:"features,omitempty"`
+
+	versionRange semver.Range
+}
+
+type CompatibilityMatrix []CompatibilityEntry
+
+// DefaultCompatibilityMatrix returns the compatibility matrix embedded in the operator
+func DefaultCompatibilityMatrix() CompatibilityMatrix {
+
+	matrix, err := ParseCompatibilityMatrix(defaultCompatibilityMatrix)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded console compatibility matrix: %v", err))
+	}
+
+	return matrix
+}
+
+// ParseCompatibilityMatrix parses the compatibility matrix and validates that the version ranges
+// are valid and that every base path is served by the nginx config of the console.
+func ParseCompatibilityMatrix(data []byte) (CompatibilityMatrix, error) {
+
+	var matrix CompatibilityMatrix
+	if err := yaml.Unmarshal(data, &matrix); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal console compatibility matrix: %w", err)
+	}
+
+	if len(matrix) == 0 {
+		return nil, fmt.Errorf("console compatibility matrix is empty")
+	}
+
+	locations := GetNginxLocations()
+	for i := range matrix {
+		entry := &matrix[i]
+
+		versionRange, err := semver.ParseRange(entry.OCPVersions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ocpVersions %q: %w", entry.OCPVersions, err)
+		}
+		entry.versionRange = versionRange
+
+		if !slices.Contains(locations, entry.BasePath) {
+			return nil, fmt.Errorf("basePath %q of ocpVersions %q is not served by the console nginx config %v", entry.BasePath, entry.OCPVersions, locations)
+		}
+	}
+
+	return matrix, nil
+}
+
+// Lookup returns the first entry matching the OpenShift version
+func (m CompatibilityMatrix) Lookup(clusterVersion string) (*CompatibilityEntry, error) {
+
+	version, err := semver.ParseTolerant(clusterVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster version %q: %w", clusterVersion, err)
+	}
+
+	for i := range m {
+		if m[i].versionRange != nil && m[i].versionRange(version) {
+			return &m[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no console compatibility entry found for cluster version %q", clusterVersion)
+}
+
+// GetNginxLocations returns the prefix locations served by the nginx config of the console
+func GetNginxLocations() []string {
+
+	return DefaultNginxConfig().Locations
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDefaultCompatibilityMatrix(t *testing.T) {
+	cases := map[string]string{
+		"4.20.3":      MAIN_BASE_PATH,
+		"4.24.0":      COMPATIBILITY_BASE_PATH,
+		"4.24.12":     COMPATIBILITY_BASE_PATH,
+		"4.24.0-ec.2": COMPATIBILITY_BASE_PATH,
+		"4.240.0":     MAIN_BASE_PATH,
+		"4.25.0":      MAIN_BASE_PATH,
+		"5.1.1":       COMPATIBILITY_BASE_PATH,
+		"5.10.0":      MAIN_BASE_PATH,
+		"4.15.1":      MAIN_BASE_PATH,
+	}
+
+	matrix := DefaultCompatibilityMatrix()
+	for version, expected := range cases {
+		entry, err := matrix.Lookup(version)
+		if err != nil {
+			t.Errorf("Lookup(%q) error: %v", version, err)
+		} else if entry.BasePath != expected {
+			t.Errorf("Lookup(%q) = %q, want %q", version, entry.BasePath, expected)
+		}
+	}
+
+	if _, err := matrix.Lookup("not-a-ver"); err == nil {
+		t.Errorf("Lookup(not-a-ver) expected an error")
+	}
+}
+
+func TestParseCompatibilityMatrix(t *testing.T) {
+	matrix, err := ParseCompatibilityMatrix([]byte(`
+- ocpVersions: ">=4.22.0-0"
+  basePath: /
+  features: [storage-consumers]
+- ocpVersions: "<4.22.0-0"
+  basePath: /compatibility/
+`))
+	if err != nil {
+		t.Fatalf("ParseCompatibilityMatrix() error: %v", err)
+	}
+
+	entry, err := matrix.Lookup("4.22.1")
+	if err != nil {
+		t.Fatalf("Lookup() error: %v", err)
+	}
+	if entry.BasePath != MAIN_BASE_PATH || !slices.Equal(entry.Features, []string{"storage-consumers"}) {
+		t.Errorf("Lookup(4.22.1) = %+v", entry)
+	}
+
+	entry, err = matrix.Lookup("4.21.9")
+	if err != nil {
+		t.Fatalf("Lookup() error: %v", err)
+	}
+	if entry.BasePath != COMPATIBILITY_BASE_PATH || len(entry.Features) != 0 {
+		t.Errorf("Lookup(4.21.9) = %+v", entry)
+	}
+}
+
+func TestParseCompatibilityMatrix_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"empty":             ``,
+		"invalid range":     `- {ocpVersions: "latest", basePath: /}`,
+		"unserved basePath": `- {ocpVersions: ">=4.0.0", basePath: /legacy/}`,
+	}
+
+	for name, data := range cases {
+		if _, err := ParseCompatibilityMatrix([]byte(data)); err == nil {
+			t.Errorf("%s: ParseCompatibilityMatrix() expected an error", name)
+		}
+	}
+}
+
+func TestGetNginxLocations(t *testing.T) {
+	locations := GetNginxLocations()
+	if !slices.Equal(locations, []string{MAIN_BASE_PATH, COMPATIBILITY_BASE_PATH}) {
+		t.Errorf("GetNginxLocations() = %v", locations)
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package console
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	consolev1 "github.com/openshift/api/console/v1"
@@ -78,83 +80,162 @@ func GetService(port int32, namespace string) *apiv1.Service {
 	}
 }
 
-func GetConsolePluginProxy(serviceNamespace string) []consolev1.ConsolePluginProxy {
-	return []consolev1.ConsolePluginProxy{
+// ConsolePluginProxyCondition is the condition under which a proxy entry is registered
+type ConsolePluginProxyCondition string
+
+const (
+	// ProxyConditionNooBaa is met when a NooBaa CR exists in the service namespace
+	ProxyConditionNooBaa ConsolePluginProxyCondition = "NooBaa"
+	// ProxyConditionROSA is met on ROSA clusters
+	ProxyConditionROSA ConsolePluginProxyCondition = "ROSA"
+)
+
+// ProxyAliasesAnnotationKey lists the aliases of the proxy entries registered by odf-operator on the plugin,
+// the entries which are no longer desired are removed even if they are no longer in the registry.
+const ProxyAliasesAnnotationKey = "odf.openshift.io/proxy-aliases"
+
+// ConsolePluginProxyEntry is a proxy entry of the plugin registry
+type ConsolePluginProxyEntry struct {
+	consolev1.ConsolePluginProxy
+	// Condition is empty for the entries which are always registered
+	Condition ConsolePluginProxyCondition
+}
+
+// GetConsolePluginProxyEntries returns the registry of the proxy entries of the plugin
+func GetConsolePluginProxyEntries(serviceNamespace string) []ConsolePluginProxyEntry {
+	return []ConsolePluginProxyEntry{
 		{
-			Alias: "provider-proxy",
-			Endpoint: consolev1.ConsolePluginProxyEndpoint{
-				Type: consolev1.ProxyTypeService,
-				Service: &consolev1.ConsolePluginProxyServiceConfig{
-					Name:      "ux-backend-proxy",
-					Namespace: serviceNamespace,
-					Port:      8888,
+			ConsolePluginProxy: consolev1.ConsolePluginProxy{
+				Alias: "provider-proxy",
+				Endpoint: consolev1.ConsolePluginProxyEndpoint{
+					Type: consolev1.ProxyTypeService,
+					Service: &consolev1.ConsolePluginProxyServiceConfig{
+						Name:      "ux-backend-proxy",
+						Namespace: serviceNamespace,
+						Port:      8888,
+					},
 				},
+				Authorization: consolev1.UserToken,
 			},
-			Authorization: consolev1.UserToken,
 		},
 		{
-			Alias: "rosa-prometheus",
-			Endpoint: consolev1.ConsolePluginProxyEndpoint{
-				Type: consolev1.ProxyTypeService,
-				Service: &consolev1.ConsolePluginProxyServiceConfig{
-					Name:      "prometheus",
-					Namespace: serviceNamespace,
-					Port:      9339,
+			ConsolePluginProxy: consolev1.ConsolePluginProxy{
+				Alias: "rosa-prometheus",
+				Endpoint: consolev1.ConsolePluginProxyEndpoint{
+					Type: consolev1.ProxyTypeService,
+					Service: &consolev1.ConsolePluginProxyServiceConfig{
+						Name:      "prometheus",
+						Namespace: serviceNamespace,
+						Port:      9339,
+					},
 				},
+				Authorization: consolev1.UserToken,
 			},
-			Authorization: consolev1.UserToken,
+			Condition: ProxyConditionROSA,
 		},
 		{
-			Alias: "s3",
-			Endpoint: consolev1.ConsolePluginProxyEndpoint{
-				Type: consolev1.ProxyTypeService,
-				Service: &consolev1.ConsolePluginProxyServiceConfig{
-					Name:      "s3",
-					Namespace: serviceNamespace,
-					Port:      443,
+			ConsolePluginProxy: consolev1.ConsolePluginProxy{
+				Alias: "s3",
+				Endpoint: consolev1.ConsolePluginProxyEndpoint{
+					Type: consolev1.ProxyTypeService,
+					Service: &consolev1.ConsolePluginProxyServiceConfig{
+						Name:      "s3",
+						Namespace: serviceNamespace,
+						Port:      443,
+					},
 				},
+				Authorization: consolev1.None,
 			},
-			Authorization: consolev1.None,
+			Condition: ProxyConditionNooBaa,
 		},
 		{
-			Alias: "iam",
-			Endpoint: consolev1.ConsolePluginProxyEndpoint{
-				Type: consolev1.ProxyTypeService,
-				Service: &consolev1.ConsolePluginProxyServiceConfig{
-					Name:      "iam",
-					Namespace: serviceNamespace,
-					Port:      443,
+			ConsolePluginProxy: consolev1.ConsolePluginProxy{
+				Alias: "iam",
+				Endpoint: consolev1.ConsolePluginProxyEndpoint{
+					Type: consolev1.ProxyTypeService,
+					Service: &consolev1.ConsolePluginProxyServiceConfig{
+						Name:      "iam",
+						Namespace: serviceNamespace,
+						Port:      443,
+					},
 				},
+				Authorization: consolev1.None,
 			},
-			Authorization: consolev1.None,
+			Condition: ProxyConditionNooBaa,
 		},
 		{
-			Alias: "s3Vector",
-			Endpoint: consolev1.ConsolePluginProxyEndpoint{
-				Type: consolev1.ProxyTypeService,
-				Service: &consolev1.ConsolePluginProxyServiceConfig{
-					Name:      "vectors",
-					Namespace: serviceNamespace,
-					Port:      443,
+			ConsolePluginProxy: consolev1.ConsolePluginProxy{
+				Alias: "s3Vector",
+				Endpoint: consolev1.ConsolePluginProxyEndpoint{
+					Type: consolev1.ProxyTypeService,
+					Service: &consolev1.ConsolePluginProxyServiceConfig{
+						Name:      "vectors",
+						Namespace: serviceNamespace,
+						Port:      443,
+					},
 				},
+				Authorization: consolev1.None,
 			},
-			Authorization: consolev1.None,
+			Condition: ProxyConditionNooBaa,
 		},
 	}
 }
 
+// GetConsolePluginProxy returns the proxy entries of the registry whose condition is met
+func GetConsolePluginProxy(serviceNamespace string, conditions map[ConsolePluginProxyCondition]bool) []consolev1.ConsolePluginProxy {
+	var proxies []consolev1.ConsolePluginProxy
+	for _, entry := range GetConsolePluginProxyEntries(serviceNamespace) {
+		if entry.Condition == "" || conditions[entry.Condition] {
+			proxies = append(proxies, entry.ConsolePluginProxy)
+		}
+	}
+	return proxies
+}
+
+// GetStaleConsolePluginProxyAliases returns the aliases owned by this operator which are not desired anymore.
+// The owned aliases are the ones of the registry and the ones recorded in the annotation of the plugin.
+func GetStaleConsolePluginProxyAliases(serviceNamespace string, desired []consolev1.ConsolePluginProxy, ownedAnnotation string) []string {
+	desiredAliases := make(map[string]bool, len(desired))
+	for _, proxy := range desired {
+		desiredAliases[proxy.Alias] = true
+	}
+
+	var owned []string
+	for _, entry := range GetConsolePluginProxyEntries(serviceNamespace) {
+		owned = append(owned, entry.Alias)
+	}
+	if ownedAnnotation != "" {
+		owned = append(owned, strings.Split(ownedAnnotation, ",")...)
+	}
+
+	var stale []string
+	for _, alias := range owned {
+		if !desiredAliases[alias] && !slices.Contains(stale, alias) {
+			stale = append(stale, alias)
+		}
+	}
+	return stale
+}
+
+// GetConsolePluginProxyAliasesAnnotation returns the value of the annotation recording the desired aliases
+func GetConsolePluginProxyAliasesAnnotation(desired []consolev1.ConsolePluginProxy) string {
+	aliases := make([]string, 0, len(desired))
+	for _, proxy := range desired {
+		aliases = append(aliases, proxy.Alias)
+	}
+	return strings.Join(aliases, ",")
+}
+
 // MergeConsolePluginProxy merges the desired proxy entries into the existing
 // list, preserving any entries added by other controllers (e.g. ocs-operator).
 // Entries owned by this operator (matched by alias) are updated in place;
 // unknown entries are kept as-is.
-func MergeConsolePluginProxy(existing []consolev1.ConsolePluginProxy, serviceNamespace string, removeAliases []string) []consolev1.ConsolePluginProxy {
+func MergeConsolePluginProxy(existing, desired []consolev1.ConsolePluginProxy, removeAliases []string) []consolev1.ConsolePluginProxy {
 	aliasesToRemove := make(map[string]bool, len(removeAliases))
 	for _, alias := range removeAliases {
 		aliasesToRemove[alias] = true
 	}
 
-	desired := GetConsolePluginProxy(serviceNamespace)
-
 	desiredAliases := make(map[string]bool, len(desired))
 	for _, proxy := range desired {
 		desiredAliases[proxy.Alias] = true
@@ -190,17 +271,64 @@ func GetConsolePluginCR(consolePort int32, serviceNamespace string) *consolev1.C
 			I18n: consolev1.ConsolePluginI18n{
 				LoadType: consolev1.Empty,
 			},
-			Proxy: GetConsolePluginProxy(serviceNamespace),
+			Proxy: GetConsolePluginProxy(serviceNamespace, nil),
 		},
 	}
 }
 
-func GetBasePath(clusterVersion string) string {
-	if strings.Contains(clusterVersion, "4.24") || strings.Contains(clusterVersion, "5.1") {
-		return COMPATIBILITY_BASE_PATH
+// CLIPlatform is an OS and architecture the odf CLI is shipped for
+type CLIPlatform struct {
+	OS   string
+	Arch string
+	Text string
+}
+
+// CLIPlatforms are the platforms served by the in-cluster CLI download service, the binary of a
+// platform is served at /<os>/<arch>/odf (odf.exe on Windows)
+var CLIPlatforms = []CLIPlatform{
+	{OS: "linux", Arch: "amd64", Text: "Linux for x86_64"},
+	{OS: "linux", Arch: "arm64", Text: "Linux for ARM 64"},
+	{OS: "linux", Arch: "ppc64le", Text: "Linux for IBM Power, little endian"},
+	{OS: "linux", Arch: "s390x", Text: "Linux for IBM Z"},
+	{OS: "darwin", Arch: "amd64", Text: "Mac for x86_64"},
+	{OS: "darwin", Arch: "arm64", Text: "Mac for ARM 64"},
+	{OS: "windows", Arch: "amd64", Text: "Windows for x86_64"},
+}
+
+const (
+	cliDownloadDescription = "With the Data Foundation CLI tool, you can effectively manage and troubleshoot your Data Foundation environment from a terminal.\n\n"
+
+	portalCLIDownloadDescription    = cliDownloadDescription + "You can find a compatible version and download the CLI tool from the Red Hat Customer Portal:\n"
+	inClusterCLIDownloadDescription = cliDownloadDescription + "Download the CLI tool compatible with this cluster for your platform:\n"
+)
+
+// GetCLIBinaryPath returns the path of the CLI binary of the platform in the in-cluster CLI download service
+func GetCLIBinaryPath(platform CLIPlatform) string {
+	binary := "odf"
+	if platform.OS == "windows" {
+		binary = "odf.exe"
+	}
+	return fmt.Sprintf("/%s/%s/%s", platform.OS, platform.Arch, binary)
+}
+
+// GetConsoleCLIDownloadInClusterLinks returns the links to the binaries served by the in-cluster CLI download service
+func GetConsoleCLIDownloadInClusterLinks(baseURL string) []consolev1.CLIDownloadLink {
+	links := make([]consolev1.CLIDownloadLink, 0, len(CLIPlatforms))
+	for _, platform := range CLIPlatforms {
+		links = append(links, consolev1.CLIDownloadLink{
+			Href: strings.TrimSuffix(baseURL, "/") + GetCLIBinaryPath(platform),
+			Text: "Download odf for " + platform.Text,
+		})
 	}
+	return links
+}
 
-	return MAIN_BASE_PATH
+// GetConsoleCLIDownloadDescription returns the description matching the links of the ConsoleCLIDownload
+func GetConsoleCLIDownloadDescription(inCluster bool) string {
+	if inCluster {
+		return inClusterCLIDownloadDescription
+	}
+	return portalCLIDownloadDescription
 }
 
 func GetConsoleCLIDownloadLinks() []consolev1.CLIDownloadLink {
@@ -218,9 +346,30 @@ func GetConsoleCLIDownloadCR() *consolev1.ConsoleCLIDownload {
 			Name: "odf-cli-downloads",
 		},
 		Spec: consolev1.ConsoleCLIDownloadSpec{
-			Description: "With the Data Foundation CLI tool, you can effectively manage and troubleshoot your Data Foundation environment from a terminal.\n\nYou can find a compatible version and download the CLI tool from the Red Hat Customer Portal:\n",
+			Description: GetConsoleCLIDownloadDescription(false),
 			DisplayName: "odf - Data Foundation Command Line Interface (CLI)",
 			Links:       GetConsoleCLIDownloadLinks(),
 		},
 	}
 }
+
+// GetConsolePluginCSP returns the Content-Security-Policy directives of the plugin. The proxied endpoints
+// are reached through the console origin and are allowed by the console defaults, only the endpoints the
+// browser connects to directly (e.g. the S3 routes of presigned URLs) have to be listed in connectSrc.
+func GetConsolePluginCSP(connectSrc []string) []consolev1.ConsolePluginCSP {
+	if len(connectSrc) == 0 {
+		return nil
+	}
+
+	values := make([]consolev1.CSPDirectiveValue, 0, len(connectSrc))
+	for _, src := range connectSrc {
+		values = append(values, consolev1.CSPDirectiveValue(src))
+	}
+
+	return []consolev1.ConsolePluginCSP{
+		{
+			Directive: consolev1.ConnectSrc,
+			Values:    values,
+		},
+	}
+}
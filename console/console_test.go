@@ -6,6 +6,11 @@ import (
 	consolev1 "github.com/openshift/api/console/v1"
 )
 
+var allProxyConditions = map[ConsolePluginProxyCondition]bool{
+	ProxyConditionNooBaa: true,
+	ProxyConditionROSA:   true,
+}
+
 func TestMergeConsolePluginProxy_PreservesUnknownEntries(t *testing.T) {
 	ns := "openshift-storage"
 	extra := consolev1.ConsolePluginProxy{
@@ -21,8 +26,8 @@ func TestMergeConsolePluginProxy_PreservesUnknownEntries(t *testing.T) {
 		Authorization: consolev1.None,
 	}
 
-	existing := append(GetConsolePluginProxy(ns), extra)
-	merged := MergeConsolePluginProxy(existing, ns, nil)
+	existing := append(GetConsolePluginProxy(ns, allProxyConditions), extra)
+	merged := MergeConsolePluginProxy(existing, GetConsolePluginProxy(ns, allProxyConditions), nil)
 
 	found := false
 	for _, p := range merged {
@@ -35,7 +40,7 @@ func TestMergeConsolePluginProxy_PreservesUnknownEntries(t *testing.T) {
 		t.Error("MergeConsolePluginProxy dropped the internalRgwS3 entry added by another controller")
 	}
 
-	desired := GetConsolePluginProxy(ns)
+	desired := GetConsolePluginProxy(ns, allProxyConditions)
 	if len(merged) != len(desired)+1 {
 		t.Errorf("expected %d proxy entries, got %d", len(desired)+1, len(merged))
 	}
@@ -58,7 +63,7 @@ func TestMergeConsolePluginProxy_UpdatesKnownEntries(t *testing.T) {
 		},
 	}
 
-	merged := MergeConsolePluginProxy(stale, ns, nil)
+	merged := MergeConsolePluginProxy(stale, GetConsolePluginProxy(ns, allProxyConditions), nil)
 
 	for _, p := range merged {
 		if p.Alias == "provider-proxy" {
@@ -74,8 +79,8 @@ func TestMergeConsolePluginProxy_UpdatesKnownEntries(t *testing.T) {
 
 func TestMergeConsolePluginProxy_EmptyExisting(t *testing.T) {
 	ns := "openshift-storage"
-	merged := MergeConsolePluginProxy(nil, ns, nil)
-	desired := GetConsolePluginProxy(ns)
+	merged := MergeConsolePluginProxy(nil, GetConsolePluginProxy(ns, allProxyConditions), nil)
+	desired := GetConsolePluginProxy(ns, allProxyConditions)
 
 	if len(merged) != len(desired) {
 		t.Errorf("expected %d proxy entries from empty existing, got %d", len(desired), len(merged))
@@ -97,8 +102,8 @@ func TestMergeConsolePluginProxy_RemovesAliases(t *testing.T) {
 		Authorization: consolev1.None,
 	}
 
-	existing := append(GetConsolePluginProxy(ns), extra)
-	merged := MergeConsolePluginProxy(existing, ns, []string{"internalRgwS3"})
+	existing := append(GetConsolePluginProxy(ns, allProxyConditions), extra)
+	merged := MergeConsolePluginProxy(existing, GetConsolePluginProxy(ns, allProxyConditions), []string{"internalRgwS3"})
 
 	for _, p := range merged {
 		if p.Alias == "internalRgwS3" {
@@ -106,8 +111,70 @@ func TestMergeConsolePluginProxy_RemovesAliases(t *testing.T) {
 		}
 	}
 
-	desired := GetConsolePluginProxy(ns)
+	desired := GetConsolePluginProxy(ns, allProxyConditions)
 	if len(merged) != len(desired) {
 		t.Errorf("expected %d proxy entries after removal, got %d", len(desired), len(merged))
 	}
 }
+
+func TestGetConsolePluginProxy_Conditions(t *testing.T) {
+	ns := "openshift-storage"
+
+	aliases := func(proxies []consolev1.ConsolePluginProxy) string {
+		return GetConsolePluginProxyAliasesAnnotation(proxies)
+	}
+
+	if got := aliases(GetConsolePluginProxy(ns, nil)); got != "provider-proxy" {
+		t.Errorf("expected only the unconditional entries, got %s", got)
+	}
+
+	noobaa := map[ConsolePluginProxyCondition]bool{ProxyConditionNooBaa: true}
+	if got := aliases(GetConsolePluginProxy(ns, noobaa)); got != "provider-proxy,s3,iam,s3Vector" {
+		t.Errorf("expected the NooBaa entries, got %s", got)
+	}
+
+	if got := aliases(GetConsolePluginProxy(ns, allProxyConditions)); got != "provider-proxy,rosa-prometheus,s3,iam,s3Vector" {
+		t.Errorf("expected all the entries, got %s", got)
+	}
+}
+
+func TestGetStaleConsolePluginProxyAliases(t *testing.T) {
+	ns := "openshift-storage"
+	desired := GetConsolePluginProxy(ns, map[ConsolePluginProxyCondition]bool{ProxyConditionNooBaa: true})
+
+	// an alias registered by a previous version is recorded in the annotation only
+	stale := GetStaleConsolePluginProxyAliases(ns, desired, "provider-proxy,s3,legacy-proxy")
+	expected := []string{"rosa-prometheus", "legacy-proxy"}
+	if len(stale) != len(expected) {
+		t.Fatalf("expected stale aliases %v, got %v", expected, stale)
+	}
+	for i := range expected {
+		if stale[i] != expected[i] {
+			t.Errorf("expected stale aliases %v, got %v", expected, stale)
+		}
+	}
+
+	existing := append(GetConsolePluginProxy(ns, allProxyConditions), consolev1.ConsolePluginProxy{Alias: "legacy-proxy"})
+	merged := MergeConsolePluginProxy(existing, desired, stale)
+	if got := GetConsolePluginProxyAliasesAnnotation(merged); got != "provider-proxy,s3,iam,s3Vector" {
+		t.Errorf("expected the stale entries to be removed, got %s", got)
+	}
+}
+
+func TestGetConsoleCLIDownloadInClusterLinks(t *testing.T) {
+	links := GetConsoleCLIDownloadInClusterLinks("https://odf-cli-downloads.apps.example.com/")
+	if len(links) != len(CLIPlatforms) {
+		t.Fatalf("expected %d links, got %d", len(CLIPlatforms), len(links))
+	}
+
+	expected := map[string]bool{
+		"https://odf-cli-downloads.apps.example.com/linux/amd64/odf":       true,
+		"https://odf-cli-downloads.apps.example.com/windows/amd64/odf.exe": true,
+	}
+	for _, link := range links {
+		delete(expected, link.Href)
+	}
+	if len(expected) != 0 {
+		t.Errorf("missing links %v in %v", expected, links)
+	}
+}
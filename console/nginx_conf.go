@@ -19,14 +19,73 @@ package console
 import (
 	"fmt"
 	"strings"
+	"text/template"
 
 	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
 )
 
+const (
+	AccessLogFormatMain = "main"
+	AccessLogFormatJSON = "json"
+
+	// DefaultHSTSMaxAge is one year, as recommended for HSTS
+	DefaultHSTSMaxAge = 31536000
+	// DefaultContentSecurityPolicy is sent with the plugin assets, which are loaded by the console and never framed
+	DefaultContentSecurityPolicy = "default-src 'self'; object-src 'none'; frame-ancestors 'none'"
+)
+
+// NginxConfig is the model of the nginx configuration serving the console plugin assets
+type NginxConfig struct {
+	// ListenPort is the port nginx listens on
+	ListenPort int32
+	// TLS holds the TLS directives, nginx defaults are used when nil
+	TLS *ocstlsv1.OpenSSLConfig
+	// Locations are the prefix locations serving the asset directories
+	Locations []string
+
+	// HSTSMaxAge is the max-age of the Strict-Transport-Security header in seconds, the header is omitted when 0
+	HSTSMaxAge int
+	// ContentTypeNosniff sends the X-Content-Type-Options: nosniff header
+	ContentTypeNosniff bool
+	// ContentSecurityPolicy is the value of the Content-Security-Policy header, the header is omitted when empty
+	ContentSecurityPolicy string
+
+	// Gzip compresses the responses on the fly
+	Gzip bool
+	// GzipStatic serves the precompressed .gz assets when present
+	GzipStatic bool
+
+	// AccessLogFormat is one of AccessLogFormatMain or AccessLogFormatJSON
+	AccessLogFormat string
+
+	// WorkerConnections is the max number of connections per nginx worker
+	WorkerConnections int
+	// ConnectionsPerClient limits the concurrent connections of a client address, unlimited when 0
+	ConnectionsPerClient int
+}
+
+// DefaultNginxConfig returns the nginx configuration used for the console plugin
+func DefaultNginxConfig() NginxConfig {
+	return NginxConfig{
+		ListenPort:            9001,
+		Locations:             []string{MAIN_BASE_PATH, COMPATIBILITY_BASE_PATH},
+		HSTSMaxAge:            DefaultHSTSMaxAge,
+		ContentTypeNosniff:    true,
+		ContentSecurityPolicy: DefaultContentSecurityPolicy,
+		Gzip:                  true,
+		GzipStatic:            true,
+		AccessLogFormat:       AccessLogFormatMain,
+		WorkerConnections:     1024,
+	}
+}
+
 // nginxConfTemplate is the full nginx.conf. The console image symlinks
 // /etc/nginx/nginx.conf -> /opt/app-root/etc/nginx.d/nginx.conf so
 // this ConfigMap content becomes the main nginx configuration.
-const nginxConfTemplate = `
+var nginxConfTemplate = template.Must(template.New("nginx.conf").Funcs(template.FuncMap{
+	"tlsDirectives": buildTLSDirectives,
+	"quote":         quoteNginxString,
+}).Parse(`
 worker_processes auto;
 error_log /var/log/nginx/error.log;
 pid /var/lib/nginx/tmp/nginx.pid;
@@ -34,7 +93,7 @@ pid /var/lib/nginx/tmp/nginx.pid;
 include /usr/share/nginx/modules/*.conf;
 
 events {
-    worker_connections 1024;
+    worker_connections {{ .WorkerConnections }};
 }
 
 http {
@@ -44,11 +103,23 @@ http {
     uwsgi_temp_path       /var/lib/nginx/tmp/uwsgi_temp;
     scgi_temp_path        /var/lib/nginx/tmp/scgi_temp;
 
+{{- if eq .AccessLogFormat "json" }}
+
+    log_format  json  escape=json '{"time":"$time_iso8601","remote_addr":"$remote_addr",'
+                      '"remote_user":"$remote_user","request":"$request","status":$status,'
+                      '"body_bytes_sent":$body_bytes_sent,"request_time":$request_time,'
+                      '"http_referer":"$http_referer","http_user_agent":"$http_user_agent",'
+                      '"http_x_forwarded_for":"$http_x_forwarded_for"}';
+
+    access_log  /var/log/nginx/access.log  json;
+{{- else }}
+
     log_format  main  '$remote_addr - $remote_user [$time_local] "$request" '
                       '$status $body_bytes_sent "$http_referer" '
                       '"$http_user_agent" "$http_x_forwarded_for"';
 
     access_log  /var/log/nginx/access.log  main;
+{{- end }}
 
     sendfile            on;
     tcp_nopush          on;
@@ -58,19 +129,34 @@ http {
 
     include             /etc/nginx/mime.types;
     default_type        application/octet-stream;
+{{- if .Gzip }}
+
+    gzip                on;
+    gzip_vary           on;
+    gzip_min_length     1024;
+    gzip_types          text/plain text/css application/javascript application/json image/svg+xml;
+{{- end }}
+{{- if .GzipStatic }}
+    gzip_static         on;
+{{- end }}
+{{- if gt .ConnectionsPerClient 0 }}
+
+    limit_conn_zone     $binary_remote_addr zone=perclient:10m;
+    limit_conn          perclient {{ .ConnectionsPerClient }};
+    limit_conn_status   429;
+{{- end }}
 
     server {
-        listen       9001 ssl;
-        listen       [::]:9001 ssl;
+        listen       {{ .ListenPort }} ssl;
+        listen       [::]:{{ .ListenPort }} ssl;
         ssl_certificate /var/serving-cert/tls.crt;
         ssl_certificate_key /var/serving-cert/tls.key;
-        %s
-        location / {
-            root   /opt/app-root/src;
-        }
-        location /compatibility/ {
+{{ tlsDirectives .TLS }}
+{{- range .Locations }}
+        location {{ . }} {
             root   /opt/app-root/src;
         }
+{{- end }}
         error_page   500 502 503 504  /50x.html;
         location = /50x.html {
             root   /usr/share/nginx/html;
@@ -78,15 +164,41 @@ http {
         ssi on;
         add_header Last-Modified $date_gmt;
         add_header Cache-Control 'no-store, no-cache, must-revalidate, proxy-revalidate, max-age=0';
+{{- if gt .HSTSMaxAge 0 }}
+        add_header Strict-Transport-Security "max-age={{ .HSTSMaxAge }}; includeSubDomains" always;
+{{- end }}
+{{- if .ContentTypeNosniff }}
+        add_header X-Content-Type-Options nosniff always;
+{{- end }}
+{{- if .ContentSecurityPolicy }}
+        add_header Content-Security-Policy {{ quote .ContentSecurityPolicy }} always;
+{{- end }}
         if_modified_since off;
         expires off;
         etag off;
     }
 }
-`
+`))
+
+// Render returns the nginx.conf for the configuration
+func (c *NginxConfig) Render() (string, error) {
+	var b strings.Builder
+	if err := nginxConfTemplate.Execute(&b, c); err != nil {
+		return "", fmt.Errorf("failed to render nginx config: %w", err)
+	}
+	return b.String(), nil
+}
 
+// GenerateNginxConf returns the default nginx.conf with the TLS directives
 func GenerateNginxConf(ossl *ocstlsv1.OpenSSLConfig) string {
-	return fmt.Sprintf(nginxConfTemplate, buildTLSDirectives(ossl))
+	config := DefaultNginxConfig()
+	config.TLS = ossl
+	conf, err := config.Render()
+	if err != nil {
+		// the default configuration is covered by the tests, it always renders
+		panic(err)
+	}
+	return conf
 }
 
 func buildTLSDirectives(ossl *ocstlsv1.OpenSSLConfig) string {
@@ -108,3 +220,8 @@ func buildTLSDirectives(ossl *ocstlsv1.OpenSSLConfig) string {
 	}
 	return b.String()
 }
+
+// quoteNginxString quotes the value as an nginx double quoted string
+func quoteNginxString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -120,3 +120,56 @@ func TestGenerateNginxConf_PreservesBaseConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestNginxConfig_SecurityHeaders(t *testing.T) {
+	conf := GenerateNginxConf(nil)
+	for _, expected := range []string{
+		`add_header Strict-Transport-Security "max-age=31536000; includeSubDomains" always;`,
+		"add_header X-Content-Type-Options nosniff always;",
+		`add_header Content-Security-Policy "default-src 'self'; object-src 'none'; frame-ancestors 'none'" always;`,
+		"gzip                on;",
+		"gzip_static         on;",
+		"access_log  /var/log/nginx/access.log  main;",
+	} {
+		if !strings.Contains(conf, expected) {
+			t.Errorf("expected default config to contain %q", expected)
+		}
+	}
+	if strings.Contains(conf, "limit_conn") {
+		t.Error("expected no connection limit by default")
+	}
+}
+
+func TestNginxConfig_Render(t *testing.T) {
+	config := DefaultNginxConfig()
+	config.HSTSMaxAge = 0
+	config.ContentTypeNosniff = false
+	config.ContentSecurityPolicy = `default-src "self"`
+	config.Gzip = false
+	config.GzipStatic = false
+	config.AccessLogFormat = AccessLogFormatJSON
+	config.ConnectionsPerClient = 20
+
+	conf, err := config.Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	for _, expected := range []string{
+		`add_header Content-Security-Policy "default-src \"self\"" always;`,
+		"log_format  json  escape=json",
+		"access_log  /var/log/nginx/access.log  json;",
+		"limit_conn          perclient 20;",
+		"listen       9001 ssl",
+		"keepalive_timeout   65;",
+	} {
+		if !strings.Contains(conf, expected) {
+			t.Errorf("expected config to contain %q", expected)
+		}
+	}
+	for _, unexpected := range []string{"Strict-Transport-Security", "X-Content-Type-Options", "gzip", "log_format  main"} {
+		if strings.Contains(conf, unexpected) {
+			t.Errorf("expected config not to contain %q", unexpected)
+		}
+	}
+}
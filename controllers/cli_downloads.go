@@ -0,0 +1,370 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	consolev1 "github.com/openshift/api/console/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/red-hat-storage/odf-operator/console"
+)
+
+const (
+	cliDownloadsName = "odf-cli-downloads"
+	cliDownloadsPort = 8080
+
+	// cliImageBinariesDir is the directory of the CLI image holding the binaries as <os>/<arch>/odf
+	cliImageBinariesDir = "/usr/share/odf-cli"
+	cliDownloadsDir     = "/srv/odf-cli"
+
+	routeApiVersion = "route.openshift.io/v1"
+	routeKind       = "Route"
+)
+
+const (
+	// ClusterConnectivityConnected links the CLI download to the Red Hat Customer Portal
+	ClusterConnectivityConnected = "connected"
+	// ClusterConnectivityDisconnected links the CLI download to the in-cluster CLI download service
+	ClusterConnectivityDisconnected = "disconnected"
+)
+
+// GetClusterConnectivity returns the connectivity of the cluster set by CLUSTER_CONNECTIVITY.
+// The cluster is connected when it is unset or invalid.
+func GetClusterConnectivity(logger logr.Logger) string {
+	connectivity := os.Getenv("CLUSTER_CONNECTIVITY")
+	switch connectivity {
+	case "":
+		return ClusterConnectivityConnected
+	case ClusterConnectivityConnected, ClusterConnectivityDisconnected:
+		return connectivity
+	}
+
+	logger.Info("Ignoring invalid CLUSTER_CONNECTIVITY, the cluster is considered connected", "value", connectivity)
+	return ClusterConnectivityConnected
+}
+
+func getCLIDownloadsLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/component": cliDownloadsName,
+		"app.kubernetes.io/name":      cliDownloadsName,
+		"app":                         cliDownloadsName,
+	}
+}
+
+func getCLIDownloadsDeployment(tolerations []corev1.Toleration) *appsv1.Deployment {
+
+	labels := getCLIDownloadsLabels()
+
+	deployment := &appsv1.Deployment{}
+	deployment.Name = cliDownloadsName
+	deployment.Namespace = OperatorNamespace
+	deployment.Labels = labels
+
+	securityContext := &corev1.SecurityContext{
+		RunAsNonRoot:             ptr.To(true),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "cli-binaries",
+			MountPath: cliDownloadsDir,
+		},
+	}
+
+	deployment.Spec = appsv1.DeploymentSpec{
+		Replicas: ptr.To(int32(1)),
+		Selector: &metav1.LabelSelector{
+			MatchLabels: labels,
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: labels,
+				Annotations: map[string]string{
+					"openshift.io/required-scc": "restricted-v2",
+				},
+			},
+			Spec: corev1.PodSpec{
+				// the binaries are copied from the CLI image, which is not required to run a server
+				InitContainers: []corev1.Container{
+					{
+						Name:            "copy-cli-binaries",
+						Image:           os.Getenv("ODF_CLI_IMAGE"),
+						ImagePullPolicy: "IfNotPresent",
+						Command:         []string{"cp", "-R", cliImageBinariesDir + "/.", cliDownloadsDir},
+						VolumeMounts:    volumeMounts,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("10m"),
+								corev1.ResourceMemory: resource.MustParse("32Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("128Mi"),
+							},
+						},
+						SecurityContext: securityContext,
+					},
+				},
+				Containers: []corev1.Container{
+					{
+						Name:            "cli-downloads-server",
+						Image:           os.Getenv("UX_BACKEND_SERVER_IMAGE"),
+						ImagePullPolicy: "IfNotPresent",
+						Command:         []string{"/cli-downloads-server"},
+						Env: []corev1.EnvVar{
+							{
+								Name:  "CLI_DOWNLOADS_PORT",
+								Value: fmt.Sprint(cliDownloadsPort),
+							},
+							{
+								Name:  "CLI_DOWNLOADS_DIR",
+								Value: cliDownloadsDir,
+							},
+						},
+						Ports: []corev1.ContainerPort{
+							{
+								ContainerPort: cliDownloadsPort,
+							},
+						},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{
+									Path: "/healthz",
+									Port: intstr.FromInt32(cliDownloadsPort),
+								},
+							},
+						},
+						VolumeMounts: volumeMounts,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("10m"),
+								corev1.ResourceMemory: resource.MustParse("32Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("128Mi"),
+							},
+						},
+						SecurityContext: securityContext,
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "cli-binaries",
+						VolumeSource: corev1.VolumeSource{
+							EmptyDir: &corev1.EmptyDirVolumeSource{},
+						},
+					},
+				},
+				Tolerations:                  tolerations,
+				AutomountServiceAccountToken: ptr.To(false),
+			},
+		},
+	}
+
+	return deployment
+}
+
+func getCLIDownloadsService() *corev1.Service {
+	service := &corev1.Service{}
+	service.Name = cliDownloadsName
+	service.Namespace = OperatorNamespace
+	service.Labels = getCLIDownloadsLabels()
+	service.Spec = corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			{
+				Name:       "http",
+				Port:       cliDownloadsPort,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt32(cliDownloadsPort),
+			},
+		},
+		Selector: map[string]string{"app": cliDownloadsName},
+		Type:     corev1.ServiceTypeClusterIP,
+	}
+	return service
+}
+
+// getCLIDownloadsRoute returns the edge terminated Route of the CLI download service. The Route is
+// unstructured as the route API is not part of the operator scheme.
+func getCLIDownloadsRoute() *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetAPIVersion(routeApiVersion)
+	route.SetKind(routeKind)
+	route.SetName(cliDownloadsName)
+	route.SetNamespace(OperatorNamespace)
+	return route
+}
+
+func getCLIDownloadsRouteSpec() map[string]interface{} {
+	return map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   cliDownloadsName,
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{
+			"targetPort": "http",
+		},
+		"tls": map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		},
+		"wildcardPolicy": "None",
+	}
+}
+
+// ensureCLIDownloads ensures the ConsoleCLIDownload of the odf CLI. On disconnected clusters the links point
+// to the in-cluster CLI download service, which is only run on these clusters. The resources of the service
+// are owned by the odf-console deployment.
+func (r *ClusterVersionReconciler) ensureCLIDownloads(ctx context.Context, owner *appsv1.Deployment) error {
+	logger := log.FromContext(ctx)
+
+	var links []consolev1.CLIDownloadLink
+	inCluster := GetClusterConnectivity(logger) == ClusterConnectivityDisconnected
+	if inCluster && os.Getenv("ODF_CLI_IMAGE") == "" {
+		logger.Info("ODF_CLI_IMAGE is not set, the CLI download links point to the customer portal")
+		inCluster = false
+	}
+
+	if inCluster {
+		host, err := r.ensureCLIDownloadsService(ctx, owner)
+		if err != nil {
+			return err
+		}
+		if host == "" {
+			// the links are set once the route is admitted
+			logger.Info("CLI download route has no host yet, the CLI download links point to the customer portal")
+			inCluster = false
+		} else {
+			links = console.GetConsoleCLIDownloadInClusterLinks("https://" + host)
+		}
+	} else if err := r.deleteCLIDownloadsService(ctx); err != nil {
+		return err
+	}
+
+	if !inCluster {
+		links = console.GetConsoleCLIDownloadLinks()
+	}
+	description := console.GetConsoleCLIDownloadDescription(inCluster)
+
+	// Create/Update ConsoleCLIDownload (CLI Tool download)
+	consoleCLIDownload := console.GetConsoleCLIDownloadCR()
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, consoleCLIDownload, func() error {
+		if !equality.Semantic.DeepEqual(consoleCLIDownload.Spec.Links, links) {
+			logger.Info("Set the CLI Tool download links", "inCluster", inCluster)
+			consoleCLIDownload.Spec.Links = links
+		}
+		consoleCLIDownload.Spec.Description = description
+		return nil
+	})
+
+	return err
+}
+
+// ensureCLIDownloadsService creates or updates the CLI download service and returns the host of its route
+func (r *ClusterVersionReconciler) ensureCLIDownloadsService(ctx context.Context, owner *appsv1.Deployment) (string, error) {
+
+	// the download service runs on the nodes of the operator
+	var tolerations []corev1.Toleration
+	if odfSub, err := GetOdfSubscription(ctx, r.Client); err != nil {
+		return "", fmt.Errorf("failed to get ODF subscription: %w", err)
+	} else if odfSub.Spec.Config != nil {
+		tolerations = odfSub.Spec.Config.Tolerations
+	}
+
+	deployment := getCLIDownloadsDeployment(tolerations)
+	desiredSpec := deployment.Spec.DeepCopy()
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		deployment.Spec = *desiredSpec
+		return controllerutil.SetControllerReference(owner, deployment, r.Scheme)
+	}); err != nil {
+		return "", fmt.Errorf("failed to create or update CLI download deployment: %w", err)
+	}
+
+	service := getCLIDownloadsService()
+	desiredServiceSpec := service.Spec.DeepCopy()
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		service.Spec.Ports = desiredServiceSpec.Ports
+		service.Spec.Selector = desiredServiceSpec.Selector
+		service.Spec.Type = desiredServiceSpec.Type
+		return controllerutil.SetControllerReference(owner, service, r.Scheme)
+	}); err != nil {
+		return "", fmt.Errorf("failed to create or update CLI download service: %w", err)
+	}
+
+	route := getCLIDownloadsRoute()
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
+		// keep the host assigned by the router
+		spec := getCLIDownloadsRouteSpec()
+		if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host != "" {
+			spec["host"] = host
+		}
+		if err := unstructured.SetNestedField(route.Object, spec, "spec"); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(owner, route, r.Scheme)
+	}); err != nil {
+		return "", fmt.Errorf("failed to create or update CLI download route: %w", err)
+	}
+
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	return host, nil
+}
+
+// deleteCLIDownloadsService deletes the CLI download service, e.g. once the cluster is connected
+func (r *ClusterVersionReconciler) deleteCLIDownloadsService(ctx context.Context) error {
+
+	// the deployment is deleted last, the service was never set up or is already deleted when it is
+	// not found in the cache, connected clusters do not send the deletes on every reconcile
+	deployment := getCLIDownloadsDeployment(nil)
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	for _, obj := range []client.Object{getCLIDownloadsRoute(), getCLIDownloadsService(), getCLIDownloadsDeployment(nil)} {
+		if err := r.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete CLI download %T: %w", obj, err)
+		}
+	}
+
+	return nil
+}
+
+// isCLIDownloadsResource returns true if the object is a resource of the CLI download service
+func isCLIDownloadsResource(obj client.Object) bool {
+	return obj.GetName() == cliDownloadsName && obj.GetNamespace() == OperatorNamespace
+}
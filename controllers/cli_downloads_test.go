@@ -0,0 +1,153 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/red-hat-storage/odf-operator/console"
+)
+
+func newCLIDownloadsTestReconciler(objs ...client.Object) *ClusterVersionReconciler {
+	scheme := newTestScheme()
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(consolev1.AddToScheme(scheme))
+	routeGVK := schema.FromAPIVersionAndKind(routeApiVersion, routeKind)
+	scheme.AddKnownTypeWithName(routeGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(routeGVK.GroupVersion().WithKind(routeKind+"List"), &unstructured.UnstructuredList{})
+
+	odfSub := &opv1a1.Subscription{}
+	odfSub.Name = "odf-operator"
+	odfSub.Namespace = OperatorNamespace
+	odfSub.Spec = &opv1a1.SubscriptionSpec{Package: OdfSubscriptionPackage}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, odfSub)...).Build()
+	return &ClusterVersionReconciler{Client: cli, Scheme: scheme}
+}
+
+func newCLIDownloadsTestOwner() *appsv1.Deployment {
+	owner := console.GetDeployment(OperatorNamespace)
+	owner.UID = "odf-console-uid"
+	return owner
+}
+
+func TestGetClusterConnectivity(t *testing.T) {
+	for value, expected := range map[string]string{
+		"":             ClusterConnectivityConnected,
+		"disconnected": ClusterConnectivityDisconnected,
+		"air-gapped":   ClusterConnectivityConnected,
+	} {
+		t.Setenv("CLUSTER_CONNECTIVITY", value)
+		assert.Equal(t, expected, GetClusterConnectivity(testLogger), value)
+	}
+}
+
+func TestEnsureCLIDownloads(t *testing.T) {
+	t.Setenv("CLUSTER_CONNECTIVITY", ClusterConnectivityDisconnected)
+	t.Setenv("ODF_CLI_IMAGE", "registry.example.com/odf-cli:latest")
+
+	// the host is assigned by the router on creation
+	route := getCLIDownloadsRoute()
+	utilruntime.Must(unstructured.SetNestedField(route.Object, "odf-cli-downloads.apps.example.com", "spec", "host"))
+
+	ctx := context.Background()
+	owner := newCLIDownloadsTestOwner()
+	r := newCLIDownloadsTestReconciler(route)
+
+	assert.NoError(t, r.ensureCLIDownloads(ctx, owner))
+
+	cliDownload := console.GetConsoleCLIDownloadCR()
+	assert.NoError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(cliDownload), cliDownload))
+	assert.Equal(t, console.GetConsoleCLIDownloadInClusterLinks("https://odf-cli-downloads.apps.example.com"), cliDownload.Spec.Links)
+	assert.Equal(t, console.GetConsoleCLIDownloadDescription(true), cliDownload.Spec.Description)
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Client.Get(ctx, client.ObjectKey{Name: cliDownloadsName, Namespace: OperatorNamespace}, deployment))
+	assert.Equal(t, "registry.example.com/odf-cli:latest", deployment.Spec.Template.Spec.InitContainers[0].Image)
+	assert.Equal(t, owner.Name, deployment.OwnerReferences[0].Name)
+
+	assert.NoError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(route), route))
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	assert.Equal(t, "odf-cli-downloads.apps.example.com", host)
+	termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+	assert.Equal(t, "edge", termination)
+
+	// the cluster is connected, the download service is deleted
+	t.Setenv("CLUSTER_CONNECTIVITY", ClusterConnectivityConnected)
+	assert.NoError(t, r.ensureCLIDownloads(ctx, owner))
+
+	assert.NoError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(cliDownload), cliDownload))
+	assert.Equal(t, console.GetConsoleCLIDownloadLinks(), cliDownload.Spec.Links)
+	assert.Equal(t, console.GetConsoleCLIDownloadDescription(false), cliDownload.Spec.Description)
+
+	for _, obj := range []client.Object{getCLIDownloadsRoute(), &corev1.Service{}, &appsv1.Deployment{}} {
+		err := r.Client.Get(ctx, client.ObjectKey{Name: cliDownloadsName, Namespace: OperatorNamespace}, obj)
+		assert.True(t, errors.IsNotFound(err), "%T", obj)
+	}
+}
+
+func TestEnsureCLIDownloadsConnected(t *testing.T) {
+	t.Setenv("CLUSTER_CONNECTIVITY", ClusterConnectivityConnected)
+
+	ctx := context.Background()
+	r := newCLIDownloadsTestReconciler()
+
+	var deletes int
+	r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+		Delete: func(ctx context.Context, cli client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			deletes++
+			return cli.Delete(ctx, obj, opts...)
+		},
+	})
+
+	// the download service was never set up, nothing is deleted
+	assert.NoError(t, r.ensureCLIDownloads(ctx, newCLIDownloadsTestOwner()))
+	assert.NoError(t, r.ensureCLIDownloads(ctx, newCLIDownloadsTestOwner()))
+	assert.Zero(t, deletes)
+
+	cliDownload := console.GetConsoleCLIDownloadCR()
+	assert.NoError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(cliDownload), cliDownload))
+	assert.Equal(t, console.GetConsoleCLIDownloadLinks(), cliDownload.Spec.Links)
+}
+
+func TestEnsureCLIDownloadsWithoutImage(t *testing.T) {
+	t.Setenv("CLUSTER_CONNECTIVITY", ClusterConnectivityDisconnected)
+	t.Setenv("ODF_CLI_IMAGE", "")
+
+	ctx := context.Background()
+	r := newCLIDownloadsTestReconciler()
+
+	assert.NoError(t, r.ensureCLIDownloads(ctx, newCLIDownloadsTestOwner()))
+
+	cliDownload := console.GetConsoleCLIDownloadCR()
+	assert.NoError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(cliDownload), cliDownload))
+	assert.Equal(t, console.GetConsoleCLIDownloadLinks(), cliDownload.Spec.Links)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
@@ -27,10 +28,13 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -44,6 +48,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/red-hat-storage/odf-operator/console"
+	"github.com/red-hat-storage/odf-operator/metrics"
 	"github.com/red-hat-storage/odf-operator/pkg/util"
 )
 
@@ -52,51 +57,90 @@ const rotatedVersionAnnotationKey = "odf.openshift.io/rotated-version"
 // ClusterVersionReconciler reconciles a ClusterVersion object
 type ClusterVersionReconciler struct {
 	client.Client
-	Scheme          *runtime.Scheme
-	ConsolePort     int32
-	cache           cache.Cache
-	controller      controller.Controller
-	tlsWatchStarted bool
+	Scheme           *runtime.Scheme
+	Recorder         events.EventRecorder
+	ConsolePort      int32
+	ServerTLSConfigs *ServerTLSConfigs
+	cache            cache.Cache
+	controller       controller.Controller
+	tlsWatchStarted  bool
+	componentWatches map[string]bool
 }
 
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions/finalizers,verbs=update
+//+kubebuilder:rbac:groups=config.openshift.io,resources=apiservers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
 //+kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="apps",resources=deployments/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=get;create;update
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consoleclidownloads,verbs=get;create;update
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consolequickstarts,verbs=get;list;create;update;delete
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups=operator.openshift.io,resources=consoles,verbs=get;patch
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;create;update;delete
 //+kubebuilder:rbac:groups=ocs.openshift.io,resources=tlsprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=noobaa.io,resources=noobaas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ocs.openshift.io,resources=storageclusters;storageclients,verbs=get;list;watch
 
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.8.3/pkg/reconcile
 func (r *ClusterVersionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
+
+	odfCsv, err := r.getOdfCsv(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	consoleOwner, err := getConsoleOwner(ctx, r.Client, odfCsv)
+	if err != nil {
+		logger.Error(err, "Could not get the owner of the console resources")
+		return ctrl.Result{}, err
+	}
+
 	r.ensureTLSProfileWatch(ctx)
+	r.ensureComponentWatches(ctx)
+	if r.ServerTLSConfigs != nil {
+		tlsSource, err := r.getTLSSource(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.ServerTLSConfigs.Update(logger, tlsSource)
+	}
 	ocpVersion, err := util.GetOpenShiftVersion(ctx, r.Client)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if err := r.ensureConsolePlugin(ctx, ocpVersion); err != nil {
+	if err := r.ensureConsolePlugin(ctx, ocpVersion, consoleOwner); err != nil {
 		logger.Error(err, "Could not ensure compatibility for ODF consolePlugin")
 		return ctrl.Result{}, err
 	}
 
-	if err := r.ensureUXBackendServer(ctx); err != nil {
+	requeueAfter, err := r.ensureUXBackendServer(ctx, odfCsv)
+	if err != nil {
 		logger.Error(err, "Could not ensure UX backend server")
 		return ctrl.Result{}, err
 	}
 
-	if err := ensureQuickStarts(ctx, r.Client, logger); err != nil {
+	quickStartConditions, err := GetQuickStartConditions(ctx, r.Client)
+	if err != nil {
+		logger.Error(err, "Could not get the QuickStart conditions")
+		return ctrl.Result{}, err
+	}
+
+	if err := ensureQuickStarts(ctx, r.Client, logger, quickStartConditions, ocpVersion, consoleOwner); err != nil {
 		logger.Error(err, "Could not ensure QuickStarts")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -107,7 +151,16 @@ func (r *ClusterVersionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			return err
 		}
 
-		return r.ensureConsolePlugin(ctx, clusterVersion)
+		odfCsv, err := r.getOdfCsv(ctx)
+		if err != nil {
+			return err
+		}
+		consoleOwner, err := getConsoleOwner(ctx, r.Client, odfCsv)
+		if err != nil {
+			return err
+		}
+
+		return r.ensureConsolePlugin(ctx, clusterVersion, consoleOwner)
 	}))
 	if err != nil {
 		return err
@@ -124,12 +177,18 @@ func (r *ClusterVersionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&appsv1.Deployment{},
 			&handler.EnqueueRequestForObject{},
-			builder.WithPredicates(uxBackendResourcePredicate("ux-backend-server")),
+			builder.WithPredicates(predicate.Or(
+				uxBackendResourcePredicate("ux-backend-server"),
+				predicate.NewPredicateFuncs(isCLIDownloadsResource),
+			)),
 		).
 		Watches(
 			&corev1.Secret{},
 			&handler.EnqueueRequestForObject{},
-			builder.WithPredicates(uxBackendResourcePredicate("ux-backend-proxy")),
+			builder.WithPredicates(predicate.Or(
+				uxBackendResourcePredicate(uxBackendProxyName),
+				uxBackendResourcePredicate(uxCertName),
+			)),
 		).
 		Watches(
 			&corev1.Service{},
@@ -141,10 +200,29 @@ func (r *ClusterVersionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&handler.EnqueueRequestForObject{},
 			builder.WithPredicates(
 				predicate.NewPredicateFuncs(func(obj client.Object) bool {
-					return obj.GetName() == "tlsprofiles.ocs.openshift.io"
+					return obj.GetName() == "tlsprofiles.ocs.openshift.io" || isComponentCRD(obj.GetName())
 				}),
 			),
 		).
+		Watches(
+			&corev1.ConfigMap{},
+			&handler.EnqueueRequestForObject{},
+			builder.WithPredicates(
+				predicate.NewPredicateFuncs(func(obj client.Object) bool {
+					return obj.GetName() == console.CompatibilityConfigMapName && obj.GetNamespace() == OperatorNamespace
+				}),
+			),
+		).
+		Watches(
+			&configv1.APIServer{},
+			&handler.EnqueueRequestForObject{},
+			builder.WithPredicates(
+				predicate.NewPredicateFuncs(func(obj client.Object) bool {
+					return obj.GetName() == "cluster"
+				}),
+				predicate.GenerationChangedPredicate{},
+			),
+		).
 		Build(r)
 
 	r.controller = controller
@@ -189,36 +267,46 @@ func (r *ClusterVersionReconciler) ensureTLSProfileWatch(ctx context.Context) {
 	logger.Info("Dynamic watch added for TLSProfile")
 }
 
-func (r *ClusterVersionReconciler) ensureConsolePlugin(ctx context.Context, clusterVersion string) error {
+// getTLSSource returns the TLS source of the servers, the TLSProfile is only read once it is watched
+func (r *ClusterVersionReconciler) getTLSSource(ctx context.Context) (*TLSSource, error) {
+	return GetTLSSource(ctx, r.Client, log.FromContext(ctx), r.tlsWatchStarted)
+}
+
+func (r *ClusterVersionReconciler) ensureConsolePlugin(ctx context.Context, clusterVersion string, owner *rbacv1.ClusterRole) error {
 	logger := log.FromContext(ctx)
+	compatibility, err := r.getConsoleCompatibility(ctx, clusterVersion)
+	if err != nil {
+		return err
+	}
 	// The base path to where the request are sent
-	basePath := console.GetBasePath(clusterVersion)
+	basePath := compatibility.BasePath
+	features := strings.Join(compatibility.Features, ",")
 
+	tlsSource, err := r.getTLSSource(ctx)
+	if err != nil {
+		return err
+	}
 	var ossl *ocstlsv1.OpenSSLConfig
-	if r.tlsWatchStarted {
-		tlsProfile := &ocstlsv1.TLSProfile{}
-		tlsProfile.Name = TLSProfileName
-		tlsProfile.Namespace = OperatorNamespace
-		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(tlsProfile), tlsProfile); client.IgnoreNotFound(err) != nil {
-			return err
-		}
-		if cfg, found := ocstlsv1.GetConfigForServer(tlsProfile, "odf.openshift.io", "console"); found {
-			if err := ocstlsv1.ValidateTLSConfig(cfg); err != nil {
-				logger.Error(err, "Invalid TLSProfile config, using nginx defaults")
-			} else {
-				ossl = ocstlsv1.OpenSSLConfigFrom(ocstlsv1.GetGoTLSConfig(cfg))
-			}
-		}
+	if cfg := tlsSource.GetServerTLSConfig(logger, TLSServerConsole); cfg != nil {
+		ossl = ocstlsv1.OpenSSLConfigFrom(ocstlsv1.GetGoTLSConfig(cfg))
 	}
 
-	nginxConf := console.GenerateNginxConf(ossl)
+	nginxConfig := GetConsoleNginxConfig(logger)
+	nginxConfig.TLS = ossl
+	nginxConf, err := nginxConfig.Render()
+	if err != nil {
+		return err
+	}
 
-	// Customer portal link (CLI Tool download)
-	portalLink := console.CUSTOMER_PORTAL_LINK
+	proxyConditions, err := GetConsolePluginProxyConditions(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	desiredProxy := console.GetConsolePluginProxy(OperatorNamespace, proxyConditions)
 
 	// Get ODF console Deployment
 	odfConsoleDeployment := console.GetDeployment(OperatorNamespace)
-	err := r.Client.Get(ctx, types.NamespacedName{
+	err = r.Client.Get(ctx, types.NamespacedName{
 		Name:      odfConsoleDeployment.Name,
 		Namespace: odfConsoleDeployment.Namespace,
 	}, odfConsoleDeployment)
@@ -259,97 +347,180 @@ func (r *ClusterVersionReconciler) ensureConsolePlugin(ctx context.Context, clus
 				odfConsolePlugin.Spec.Backend.Service.BasePath = basePath
 			}
 		}
-		// MergeConsolePluginProxy merges the desired proxy entries into the existing.
-		// To remove stale proxy entries, pass the aliases of the stale entries to the function (currently nil)
-		odfConsolePlugin.Spec.Proxy = console.MergeConsolePluginProxy(odfConsolePlugin.Spec.Proxy, OperatorNamespace, nil)
-		return nil
+		if odfConsolePlugin.Annotations == nil {
+			odfConsolePlugin.Annotations = map[string]string{}
+		}
+		if features != "" {
+			odfConsolePlugin.Annotations[console.FeaturesAnnotationKey] = features
+		} else {
+			delete(odfConsolePlugin.Annotations, console.FeaturesAnnotationKey)
+		}
+		odfConsolePlugin.Spec.ContentSecurityPolicy = console.GetConsolePluginCSP(GetConsolePluginConnectSrc())
+		// MergeConsolePluginProxy merges the desired proxy entries into the existing, keeping the entries of
+		// other controllers. The entries owned by this operator whose condition is no longer met are removed.
+		staleAliases := console.GetStaleConsolePluginProxyAliases(OperatorNamespace, desiredProxy,
+			odfConsolePlugin.Annotations[console.ProxyAliasesAnnotationKey])
+		odfConsolePlugin.Spec.Proxy = console.MergeConsolePluginProxy(odfConsolePlugin.Spec.Proxy, desiredProxy, staleAliases)
+		odfConsolePlugin.Annotations[console.ProxyAliasesAnnotationKey] = console.GetConsolePluginProxyAliasesAnnotation(desiredProxy)
+		return setConsoleOwner(owner, odfConsolePlugin, r.Scheme)
 	})
 	if err != nil && !errors.IsAlreadyExists(err) {
 		return err
 	}
 
-	// Create/Update ConsoleCLIDownload (CLI Tool download)
-	consoleCLIDownload := console.GetConsoleCLIDownloadCR()
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, consoleCLIDownload, func() error {
-		if currentPortalLink := consoleCLIDownload.Spec.Links[0].Href; currentPortalLink != portalLink {
-			logger.Info(fmt.Sprintf("Set the customer portal link for CLI Tool '%s'", portalLink))
-			consoleCLIDownload.Spec.Links[0].Href = portalLink
-		}
-		if len(consoleCLIDownload.Spec.Links) != 1 {
-			consoleCLIDownload.Spec.Links = console.GetConsoleCLIDownloadLinks()
-		}
-		return nil
-	})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	// Enable the plugin in the console operator config, unless opted out
+	if err := ensureConsolePluginEnabled(ctx, r.Client, logger, IsConsolePluginEnabled(logger)); err != nil {
+		return err
+	}
+
+	// Create/Update ConsoleCLIDownload (CLI Tool download) and the in-cluster CLI download service
+	if err := r.ensureCLIDownloads(ctx, odfConsoleDeployment); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *ClusterVersionReconciler) ensureUXBackendServer(ctx context.Context) error {
+// getConsoleCompatibility returns the console compatibility entry of the cluster version. The matrix from the
+// compatibility configmap is used when present and valid, the embedded matrix is used otherwise.
+func (r *ClusterVersionReconciler) getConsoleCompatibility(ctx context.Context, clusterVersion string) (*console.CompatibilityEntry, error) {
 	logger := log.FromContext(ctx)
+
+	matrix := console.DefaultCompatibilityMatrix()
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = console.CompatibilityConfigMapName
+	cm.Namespace = OperatorNamespace
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cm), cm); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	} else if err == nil {
+		if override, err := console.ParseCompatibilityMatrix([]byte(cm.Data[console.CompatibilityConfigMapKey])); err != nil {
+			logger.Error(err, "Invalid console compatibility configmap, using the embedded matrix", "configmap", cm.Name)
+		} else {
+			matrix = override
+		}
+	}
+
+	entry, err := matrix.Lookup(clusterVersion)
+	if err != nil {
+		logger.Error(err, "No console compatibility entry found, using the main base path")
+		return &console.CompatibilityEntry{BasePath: console.MAIN_BASE_PATH}, nil
+	}
+
+	return entry, nil
+}
+
+// getOdfCsv returns the CSV of the running odf-operator
+func (r *ClusterVersionReconciler) getOdfCsv(ctx context.Context) (*opv1a1.ClusterServiceVersion, error) {
 	odfCsvName, err := util.GetConditionName(r.Client)
 	if err != nil {
-		return fmt.Errorf("failed to get ODF CSV name: %w", err)
+		return nil, fmt.Errorf("failed to get ODF CSV name: %w", err)
 	}
 
 	odfCsv := &opv1a1.ClusterServiceVersion{}
 	odfCsv.Name = odfCsvName
 	odfCsv.Namespace = OperatorNamespace
 	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(odfCsv), odfCsv); err != nil {
-		return fmt.Errorf("failed to get ODF CSV %s/%s: %w", odfCsv.Namespace, odfCsv.Name, err)
+		return nil, fmt.Errorf("failed to get ODF CSV %s/%s: %w", odfCsv.Namespace, odfCsv.Name, err)
 	}
 
-	// TODO: remove the following check in future version
-	// the following is to check if ocs-operator and odf-operator csvs are at same version
-	ocsCsvName := strings.Replace(odfCsvName, "odf", "ocs", 1)
-	ocsCSV := &opv1a1.ClusterServiceVersion{}
-	ocsCSV.Name = ocsCsvName
-	ocsCSV.Namespace = OperatorNamespace
-	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(ocsCSV), ocsCSV); err != nil {
-		// The OCS operator CSV must match the ODF operator CSV version,
-		// this is because the ux-backend-server deployment is moved from ocs-operator to odf-operator
-		// during upgrade there may be a collision of ownership between the two operators
-		logger.Error(err, "Skipping UX backend server setup")
-		return fmt.Errorf("OCS operator CSV must match the ODF operator CSV version: %w", err)
+	return odfCsv, nil
+}
+
+// ensureUXBackendServer ensures the ux-backend server and returns the time after which its secrets have to be checked again
+func (r *ClusterVersionReconciler) ensureUXBackendServer(ctx context.Context, odfCsv *opv1a1.ClusterServiceVersion) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	// the ux-backend resources moved from ocs-operator, they are reconciled once ocs-operator released them
+	handover := &Handover{Client: r.Client, Scheme: r.Scheme, Owner: odfCsv}
+	uxBackendServerObjects := []client.Object{
+		getUXBackendServerSecret(),
+		getUXBackendServerService(),
+		getUXBackendServerNetworkPolicy(),
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "ux-backend-server", Namespace: OperatorNamespace}},
+		getUXBackendServerPodDisruptionBudget(),
+	}
+	for _, obj := range uxBackendServerObjects {
+		if err := handover.Acquire(ctx, obj); IsWaitingForRelease(err) {
+			logger.Info("Skipping UX backend server setup", "reason", err.Error())
+			if r.Recorder != nil {
+				r.Recorder.Eventf(odfCsv, nil, corev1.EventTypeNormal, "WaitingForRelease", "Handover", "%v", err)
+			}
+			return handoverRetryInterval, nil
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to check the handover of the UX backend server: %w", err)
+		}
 	}
 
 	logger.Info("Ensuring UX backend server secret")
+	rotationConfig := GetSecretRotationConfig(logger)
+	now := time.Now()
 	uxBackendServerSecret := getUXBackendServerSecret()
 	odfMajorMinorVersion := fmt.Sprintf("%d.%d", odfCsv.Spec.Version.Major, odfCsv.Spec.Version.Minor)
-	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, uxBackendServerSecret, func() error {
-		uxBackendServerSecret.SetOwnerReferences(nil)
-		currentVersion := uxBackendServerSecret.Annotations[rotatedVersionAnnotationKey]
-		if currentVersion != odfMajorMinorVersion {
-			secret, err := generateSessionSecret()
-			if err != nil {
-				return fmt.Errorf("failed to generate session secret: %w", err)
-			}
-			uxBackendServerSecret.StringData = map[string]string{
-				"session_secret": secret,
-			}
-			if uxBackendServerSecret.Annotations == nil {
-				uxBackendServerSecret.Annotations = make(map[string]string)
-			}
-			uxBackendServerSecret.Annotations[rotatedVersionAnnotationKey] = odfMajorMinorVersion
+	uxBackendServerRolledOut, err := r.isUXBackendServerRolledOut(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the rollout of the UX backend server: %w", err)
+	}
+	var sessionSecretRotated bool
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, uxBackendServerSecret, func() error {
+		rotated, err := rotateSessionSecret(uxBackendServerSecret, odfMajorMinorVersion, now, rotationConfig, uxBackendServerRolledOut)
+		if err != nil {
+			return err
 		}
-		return controllerutil.SetControllerReference(odfCsv, uxBackendServerSecret, r.Scheme)
+		sessionSecretRotated = rotated
+		return handover.Claim(uxBackendServerSecret)
 	}); err != nil {
-		return fmt.Errorf("failed to create or update UX backend server secret: %w", err)
+		return 0, fmt.Errorf("failed to create or update UX backend server secret: %w", err)
+	}
+	if sessionSecretRotated {
+		logger.Info("UX backend server session secret rotated", "key", uxBackendServerSecret.Annotations[sessionSecretKeyAnnotationKey])
+		metrics.ReportUXBackendSecretRotation(uxBackendServerSecret.Name)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(uxBackendServerSecret, nil, corev1.EventTypeNormal, "SecretRotated", "Rotate",
+				"Session secret of %s rotated, the ux-backend pods are rolled out", uxBackendServerSecret.Name)
+		}
+	}
+
+	uxCertNotBefore, uxCertExpiry, err := r.ensureUXCertRotation(ctx, logger, now, rotationConfig)
+	if err != nil {
+		return 0, err
+	}
+	onboardingPrivateKeyExpiry, err := r.getOnboardingPrivateKeyExpiry(ctx, logger)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get onboarding private key: %w", err)
+	}
+
+	// the next rotation, overlap end or expiry warning requeues the reconcile, the end of a rollout is watched
+	var requeueTimes []time.Time
+	if next, ok := getSessionSecretNextRotation(uxBackendServerSecret, rotationConfig); ok {
+		requeueTimes = append(requeueTimes, next)
+	}
+	if overlapEnd, ok := getSessionSecretOverlapEnd(uxBackendServerSecret, rotationConfig); ok {
+		requeueTimes = append(requeueTimes, overlapEnd)
 	}
+	if rotationConfig.Interval > 0 {
+		rotatedAt, _ := time.Parse(time.RFC3339, uxBackendServerSecret.Annotations[rotatedAtAnnotationKey])
+		secretExpiry{secret: uxBackendServerSecret, expiry: rotatedAt.Add(rotationConfig.Interval)}.report(nil, now, rotationConfig)
+	}
+	for _, expiry := range []*secretExpiry{uxCertExpiry, onboardingPrivateKeyExpiry} {
+		if expiry == nil {
+			continue
+		}
+		expiry.report(r.Recorder, now, rotationConfig)
+		requeueTimes = append(requeueTimes, expiry.expiry.Add(-rotationConfig.ExpiryWarning), expiry.expiry)
+	}
+	requeueAfter := getRequeueAfter(now, requeueTimes...)
 
 	logger.Info("Ensuring UX backend server service")
 	uxBackendServerService := getUXBackendServerService()
 	desiredService := uxBackendServerService.Spec.DeepCopy()
 	annotations := uxBackendServerService.Annotations
 	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, uxBackendServerService, func() error {
-		uxBackendServerService.SetOwnerReferences(nil)
 		uxBackendServerService.Spec = *desiredService
 		uxBackendServerService.Annotations = annotations
-		return controllerutil.SetControllerReference(odfCsv, uxBackendServerService, r.Scheme)
+		return handover.Claim(uxBackendServerService)
 	}); err != nil {
-		return fmt.Errorf("failed to create or update UX backend server service: %w", err)
+		return 0, fmt.Errorf("failed to create or update UX backend server service: %w", err)
 	}
 
 	desiredUXBackendServerNetworkPolicy := getUXBackendServerNetworkPolicy()
@@ -358,30 +529,56 @@ func (r *ClusterVersionReconciler) ensureUXBackendServer(ctx context.Context) er
 	uxBackendServerNetworkPolicy.Namespace = desiredUXBackendServerNetworkPolicy.Namespace
 	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, uxBackendServerNetworkPolicy, func() error {
 		uxBackendServerNetworkPolicy.Spec = desiredUXBackendServerNetworkPolicy.Spec
-		return controllerutil.SetControllerReference(odfCsv, uxBackendServerNetworkPolicy, r.Scheme)
+		return handover.Claim(uxBackendServerNetworkPolicy)
 	}); err != nil {
-		return fmt.Errorf("failed to create or update UX backend server network policy: %w", err)
+		return 0, fmt.Errorf("failed to create or update UX backend server network policy: %w", err)
+	}
+
+	// Get the placement from ODF subscription for deployment
+	odfSub, err := GetOdfSubscription(ctx, r.Client)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get ODF subscription: %w", err)
+	}
+	uxBackendConfig, err := GetUXBackendConfig(ctx, r.Client, logger, odfSub)
+	if err != nil {
+		return 0, err
 	}
 
-	// Get tolerations from ODF subscription for deployment
-	var tolerations []corev1.Toleration
-	if odfSub, err := GetOdfSubscription(ctx, r.Client); err != nil {
-		return fmt.Errorf("failed to get ODF subscription: %w", err)
-	} else if odfSub.Spec.Config != nil {
-		tolerations = odfSub.Spec.Config.Tolerations
+	tlsSource, err := r.getTLSSource(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get TLS source: %w", err)
 	}
+	serverTLSConfig := tlsSource.GetServerTLSConfig(logger, TLSServerUXBackend)
+	proxyTLSConfig := tlsSource.GetServerTLSConfig(logger, TLSServerUXBackendProxy)
 
 	// Create/Update UX backend server deployment
 	logger.Info("Ensuring UX backend server deployment")
-	uxBackendServerDeployment := getUXBackendServerDeployment(tolerations)
+	sessionSecretKey := uxBackendServerSecret.Annotations[sessionSecretKeyAnnotationKey]
+	uxBackendServerDeployment := getUXBackendServerDeployment(uxBackendConfig, sessionSecretKey, serverTLSConfig, proxyTLSConfig)
+	if uxCertNotBefore != "" {
+		uxBackendServerDeployment.Spec.Template.Annotations[uxCertNotBeforeAnnotationKey] = uxCertNotBefore
+	}
 	desiredSpec := uxBackendServerDeployment.Spec.DeepCopy()
 	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, uxBackendServerDeployment, func() error {
-		uxBackendServerDeployment.SetOwnerReferences(nil)
 		uxBackendServerDeployment.Spec = *desiredSpec
-		return controllerutil.SetControllerReference(odfCsv, uxBackendServerDeployment, r.Scheme)
+		return handover.Claim(uxBackendServerDeployment)
 	}); err != nil {
-		return fmt.Errorf("failed to create or update UX backend server deployment: %w", err)
+		return 0, fmt.Errorf("failed to create or update UX backend server deployment: %w", err)
 	}
 
-	return nil
+	uxBackendServerPDB := getUXBackendServerPodDisruptionBudget()
+	if uxBackendConfig.Replicas > 1 {
+		logger.Info("Ensuring UX backend server pod disruption budget")
+		desiredPDBSpec := uxBackendServerPDB.Spec.DeepCopy()
+		if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, uxBackendServerPDB, func() error {
+			uxBackendServerPDB.Spec = *desiredPDBSpec
+			return handover.Claim(uxBackendServerPDB)
+		}); err != nil {
+			return 0, fmt.Errorf("failed to create or update UX backend server pod disruption budget: %w", err)
+		}
+	} else if err := r.Client.Delete(ctx, uxBackendServerPDB); client.IgnoreNotFound(err) != nil {
+		return 0, fmt.Errorf("failed to delete UX backend server pod disruption budget: %w", err)
+	}
+
+	return requeueAfter, nil
 }
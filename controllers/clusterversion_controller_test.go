@@ -0,0 +1,105 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/red-hat-storage/odf-operator/console"
+)
+
+func TestGetConsoleCompatibility(t *testing.T) {
+	ctx := context.Background()
+
+	newConfigMap := func(matrix string) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		cm.Name = console.CompatibilityConfigMapName
+		cm.Namespace = OperatorNamespace
+		cm.Data = map[string]string{console.CompatibilityConfigMapKey: matrix}
+		return cm
+	}
+
+	override := newConfigMap(`
+- ocpVersions: ">=4.22.0-0"
+  basePath: /compatibility/
+  features: [storage-consumers]
+- ocpVersions: "<4.22.0-0"
+  basePath: /
+`)
+	noMatch := newConfigMap(`- {ocpVersions: ">=9.0.0-0", basePath: /}`)
+
+	tests := []struct {
+		name           string
+		objs           []client.Object
+		clusterVersion string
+		wantBasePath   string
+		wantFeatures   []string
+	}{
+		{
+			name:           "embedded matrix main base path",
+			clusterVersion: "4.20.3",
+			wantBasePath:   console.MAIN_BASE_PATH,
+		},
+		{
+			name:           "embedded matrix compatibility base path",
+			clusterVersion: "4.24.0-ec.2",
+			wantBasePath:   console.COMPATIBILITY_BASE_PATH,
+		},
+		{
+			name:           "configmap overrides the embedded matrix",
+			objs:           []client.Object{override},
+			clusterVersion: "4.22.1",
+			wantBasePath:   console.COMPATIBILITY_BASE_PATH,
+			wantFeatures:   []string{"storage-consumers"},
+		},
+		{
+			name:           "invalid configmap falls back to the embedded matrix",
+			objs:           []client.Object{newConfigMap("- {ocpVersions: latest, basePath: /}")},
+			clusterVersion: "4.24.0",
+			wantBasePath:   console.COMPATIBILITY_BASE_PATH,
+		},
+		{
+			name:           "no matching entry uses the main base path",
+			objs:           []client.Object{noMatch},
+			clusterVersion: "4.24.0",
+			wantBasePath:   console.MAIN_BASE_PATH,
+		},
+		{
+			name:           "invalid cluster version uses the main base path",
+			clusterVersion: "not-a-version",
+			wantBasePath:   console.MAIN_BASE_PATH,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(tt.objs...).Build()
+			r := &ClusterVersionReconciler{Client: cli}
+
+			entry, err := r.getConsoleCompatibility(ctx, tt.clusterVersion)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBasePath, entry.BasePath)
+			assert.Equal(t, tt.wantFeatures, entry.Features)
+		})
+	}
+}
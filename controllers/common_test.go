@@ -39,6 +39,7 @@ var (
 
 func init() {
 
+	LoadEnv()
 	log.SetLogger(zap.New(zap.UseDevMode(true)))
 
 	testScheme = runtime.NewScheme()
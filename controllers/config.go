@@ -37,6 +37,9 @@ type OdfOperatorConfigMapRecord struct {
 	   namespace: openshift-storage
 	   scaleUpOnInstanceOf:
 	     - storageclusters.ocs.openshift.io
+	   carryOverFields:
+	     - spec.replicas
+	     - spec.template.spec.containers[*].resources
 	   ---------------------------------------
 	   channel: beta
 	   csv: odf-prometheus-operator.v4.18.0
@@ -52,6 +55,9 @@ type OdfOperatorConfigMapRecord struct {
 	Pkg                 string   `yaml:"pkg"`
 	Namespace           string   `yaml:"namespace"`
 	ScaleUpOnInstanceOf []string `yaml:"scaleUpOnInstanceOf"`
+	// CarryOverFields lists the deployment spec paths copied from the previous csv on upgrade,
+	// DefaultCsvCarryOverFields is used when it is empty
+	CarryOverFields []string `yaml:"carryOverFields"`
 }
 
 func GetOdfConfigMap(ctx context.Context, cli client.Client, logger logr.Logger) (corev1.ConfigMap, error) {
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/red-hat-storage/odf-operator/console"
+)
+
+const (
+	// consoleHeaderDisabled disables a security header when set as its value
+	consoleHeaderDisabled = "off"
+)
+
+// GetConsoleNginxConfig returns the nginx config of the console plugin with the settings of the operator
+// configuration applied on top of the defaults. Invalid settings are logged and the defaults are kept.
+func GetConsoleNginxConfig(logger logr.Logger) console.NginxConfig {
+
+	config := console.DefaultNginxConfig()
+
+	if value := os.Getenv("CONSOLE_HSTS_MAX_AGE"); value == consoleHeaderDisabled {
+		config.HSTSMaxAge = 0
+	} else if value != "" {
+		if maxAge, err := strconv.Atoi(value); err != nil || maxAge < 0 {
+			logger.Error(fmt.Errorf("invalid value %q", value), "ignoring CONSOLE_HSTS_MAX_AGE")
+		} else {
+			config.HSTSMaxAge = maxAge
+		}
+	}
+
+	if value := os.Getenv("CONSOLE_CONTENT_TYPE_NOSNIFF"); value != "" {
+		if nosniff, err := strconv.ParseBool(value); err != nil {
+			logger.Error(err, "ignoring CONSOLE_CONTENT_TYPE_NOSNIFF")
+		} else {
+			config.ContentTypeNosniff = nosniff
+		}
+	}
+
+	if value := os.Getenv("CONSOLE_CONTENT_SECURITY_POLICY"); value == consoleHeaderDisabled {
+		config.ContentSecurityPolicy = ""
+	} else if value != "" {
+		config.ContentSecurityPolicy = value
+	}
+
+	if value := os.Getenv("CONSOLE_GZIP"); value != "" {
+		if gzip, err := strconv.ParseBool(value); err != nil {
+			logger.Error(err, "ignoring CONSOLE_GZIP")
+		} else {
+			config.Gzip = gzip
+			config.GzipStatic = gzip
+		}
+	}
+
+	if value := os.Getenv("CONSOLE_ACCESS_LOG_FORMAT"); value != "" {
+		if !slices.Contains([]string{console.AccessLogFormatMain, console.AccessLogFormatJSON}, value) {
+			logger.Error(fmt.Errorf("invalid value %q", value), "ignoring CONSOLE_ACCESS_LOG_FORMAT")
+		} else {
+			config.AccessLogFormat = value
+		}
+	}
+
+	if value := os.Getenv("CONSOLE_CONNECTIONS_PER_CLIENT"); value != "" {
+		if limit, err := strconv.Atoi(value); err != nil || limit < 0 {
+			logger.Error(fmt.Errorf("invalid value %q", value), "ignoring CONSOLE_CONNECTIONS_PER_CLIENT")
+		} else {
+			config.ConnectionsPerClient = limit
+		}
+	}
+
+	return config
+}
+
+// GetConsolePluginConnectSrc returns the CSP connect-src values of the console plugin from the operator configuration
+func GetConsolePluginConnectSrc() []string {
+	return strings.Fields(strings.ReplaceAll(os.Getenv("CONSOLE_PLUGIN_CSP_CONNECT_SRC"), ",", " "))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/red-hat-storage/odf-operator/console"
+)
+
+func TestGetConsoleNginxConfig(t *testing.T) {
+	assert.Equal(t, console.DefaultNginxConfig(), GetConsoleNginxConfig(testLogger))
+
+	t.Setenv("CONSOLE_HSTS_MAX_AGE", "off")
+	t.Setenv("CONSOLE_CONTENT_TYPE_NOSNIFF", "false")
+	t.Setenv("CONSOLE_CONTENT_SECURITY_POLICY", "default-src 'self'")
+	t.Setenv("CONSOLE_GZIP", "false")
+	t.Setenv("CONSOLE_ACCESS_LOG_FORMAT", "json")
+	t.Setenv("CONSOLE_CONNECTIONS_PER_CLIENT", "50")
+
+	config := GetConsoleNginxConfig(testLogger)
+	assert.Equal(t, 0, config.HSTSMaxAge)
+	assert.False(t, config.ContentTypeNosniff)
+	assert.Equal(t, "default-src 'self'", config.ContentSecurityPolicy)
+	assert.False(t, config.Gzip)
+	assert.False(t, config.GzipStatic)
+	assert.Equal(t, console.AccessLogFormatJSON, config.AccessLogFormat)
+	assert.Equal(t, 50, config.ConnectionsPerClient)
+
+	// invalid values keep the defaults
+	t.Setenv("CONSOLE_HSTS_MAX_AGE", "a year")
+	t.Setenv("CONSOLE_ACCESS_LOG_FORMAT", "xml")
+	t.Setenv("CONSOLE_CONNECTIONS_PER_CLIENT", "-1")
+
+	config = GetConsoleNginxConfig(testLogger)
+	assert.Equal(t, console.DefaultHSTSMaxAge, config.HSTSMaxAge)
+	assert.Equal(t, console.AccessLogFormatMain, config.AccessLogFormat)
+	assert.Equal(t, 0, config.ConnectionsPerClient)
+}
+
+func TestGetConsolePluginConnectSrc(t *testing.T) {
+	assert.Empty(t, GetConsolePluginConnectSrc())
+
+	t.Setenv("CONSOLE_PLUGIN_CSP_CONNECT_SRC", "https://s3.example.com, https://iam.example.com")
+	assert.Equal(t, []string{"https://s3.example.com", "https://iam.example.com"}, GetConsolePluginConnectSrc())
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/red-hat-storage/odf-operator/console"
+)
+
+const (
+	consoleOperatorApiVersion = "operator.openshift.io/v1"
+	consoleOperatorKind       = "Console"
+	consoleOperatorName       = "cluster"
+
+	// The labels set by OLM on the cluster scoped resources it creates for a CSV
+	OlmOwnerLabel          = "olm.owner"
+	OlmOwnerKindLabel      = "olm.owner.kind"
+	OlmOwnerNamespaceLabel = "olm.owner.namespace"
+)
+
+// IsConsolePluginEnabled returns false if CONSOLE_PLUGIN_ENABLED opts out of enabling the odf-console plugin
+// in the console operator config. Invalid values are logged and the plugin stays enabled.
+func IsConsolePluginEnabled(logger logr.Logger) bool {
+	value := os.Getenv("CONSOLE_PLUGIN_ENABLED")
+	if value == "" {
+		return true
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Error(err, "ignoring CONSOLE_PLUGIN_ENABLED")
+		return true
+	}
+
+	return enabled
+}
+
+// ensureConsolePluginEnabled adds the odf-console plugin to the spec.plugins of the console operator config,
+// or removes it when the plugin is disabled. The config is unstructured as the operator API is not part of the
+// operator scheme, clusters without the console capability have no config and are skipped.
+func ensureConsolePluginEnabled(ctx context.Context, cli client.Client, logger logr.Logger, enabled bool) error {
+
+	consoleOperator := &unstructured.Unstructured{}
+	consoleOperator.SetAPIVersion(consoleOperatorApiVersion)
+	consoleOperator.SetKind(consoleOperatorKind)
+	consoleOperator.SetName(consoleOperatorName)
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(consoleOperator), consoleOperator); err != nil {
+		if client.IgnoreNotFound(err) == nil || meta.IsNoMatchError(err) {
+			logger.Info("console operator config not found, skipping the odf-console plugin registration")
+			return nil
+		}
+		return err
+	}
+
+	plugins, _, err := unstructured.NestedStringSlice(consoleOperator.Object, "spec", "plugins")
+	if err != nil {
+		return err
+	}
+
+	registered := slices.Contains(plugins, console.ODF_CONSOLE)
+	if registered == enabled {
+		return nil
+	}
+
+	if enabled {
+		plugins = append(plugins, console.ODF_CONSOLE)
+	} else {
+		plugins = slices.DeleteFunc(plugins, func(plugin string) bool { return plugin == console.ODF_CONSOLE })
+	}
+
+	// the list is replaced as a whole, the lock keeps the plugins enabled concurrently by others
+	patch := client.MergeFromWithOptions(consoleOperator.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if err := unstructured.SetNestedStringSlice(consoleOperator.Object, plugins, "spec", "plugins"); err != nil {
+		return err
+	}
+	if err := cli.Patch(ctx, consoleOperator, patch); err != nil {
+		return err
+	}
+
+	if enabled {
+		logger.Info("odf-console plugin enabled in the console operator config")
+	} else {
+		logger.Info("odf-console plugin removed from the console operator config")
+	}
+
+	return nil
+}
+
+// cleanupConsole deletes the quickstarts and removes the odf-console plugin from the console operator config
+// TODO: This function is not used, a call for this function need to be introduced whenever we resolve ODF uninstallation techdebt
+func cleanupConsole(ctx context.Context, cli client.Client, logger logr.Logger) { //nolint:unused
+
+	deleteQuickStarts(ctx, cli, logger)
+
+	if err := ensureConsolePluginEnabled(ctx, cli, logger, false); err != nil {
+		logger.Error(err, "failed to remove the odf-console plugin from the console operator config")
+	}
+}
+
+// getConsoleOwner returns a ClusterRole created by OLM for the clusterPermissions of the odf-operator CSV, or nil
+// if there is none. OLM deletes it with the CSV, the cluster scoped console resources are owned by it so that they
+// are garbage collected on uninstall, as the namespaced CSV cannot own them.
+func getConsoleOwner(ctx context.Context, cli client.Client, odfCsv *opv1a1.ClusterServiceVersion) (*rbacv1.ClusterRole, error) {
+
+	clusterRoles := &rbacv1.ClusterRoleList{}
+	if err := cli.List(ctx, clusterRoles, client.MatchingLabels{
+		OlmOwnerLabel:          odfCsv.Name,
+		OlmOwnerKindLabel:      opv1a1.ClusterServiceVersionKind,
+		OlmOwnerNamespaceLabel: odfCsv.Namespace,
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(clusterRoles.Items) == 0 {
+		return nil, nil
+	}
+
+	slices.SortFunc(clusterRoles.Items, func(a, b rbacv1.ClusterRole) int { return strings.Compare(a.Name, b.Name) })
+	return &clusterRoles.Items[0], nil
+}
+
+// setConsoleOwner adds the owner reference of the console owner to the console resource. The references of the
+// previous odf-operator CSVs are kept, the resource survives an upgrade as long as one of its owners exists.
+func setConsoleOwner(owner *rbacv1.ClusterRole, obj client.Object, scheme *runtime.Scheme) error {
+
+	if owner == nil {
+		return nil
+	}
+
+	return controllerutil.SetOwnerReference(owner, obj, scheme)
+}
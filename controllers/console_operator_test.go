@@ -0,0 +1,131 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsConsolePluginEnabled(t *testing.T) {
+	assert.True(t, IsConsolePluginEnabled(testLogger))
+
+	t.Setenv("CONSOLE_PLUGIN_ENABLED", "false")
+	assert.False(t, IsConsolePluginEnabled(testLogger))
+
+	t.Setenv("CONSOLE_PLUGIN_ENABLED", "no thanks")
+	assert.True(t, IsConsolePluginEnabled(testLogger))
+}
+
+func TestEnsureConsolePluginEnabled(t *testing.T) {
+	scheme := newTestScheme()
+	consoleGVK := schema.FromAPIVersionAndKind(consoleOperatorApiVersion, consoleOperatorKind)
+	scheme.AddKnownTypeWithName(consoleGVK, &unstructured.Unstructured{})
+
+	consoleOperator := &unstructured.Unstructured{}
+	consoleOperator.SetGroupVersionKind(consoleGVK)
+	consoleOperator.SetName(consoleOperatorName)
+	utilruntime.Must(unstructured.SetNestedStringSlice(consoleOperator.Object, []string{"monitoring-plugin"}, "spec", "plugins"))
+
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(consoleOperator).Build()
+
+	getPlugins := func() []string {
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(consoleGVK)
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(consoleOperator), found))
+		plugins, _, _ := unstructured.NestedStringSlice(found.Object, "spec", "plugins")
+		return plugins
+	}
+
+	assert.NoError(t, ensureConsolePluginEnabled(ctx, cli, testLogger, true))
+	assert.Equal(t, []string{"monitoring-plugin", "odf-console"}, getPlugins())
+
+	// the plugin is only added once
+	assert.NoError(t, ensureConsolePluginEnabled(ctx, cli, testLogger, true))
+	assert.Equal(t, []string{"monitoring-plugin", "odf-console"}, getPlugins())
+
+	// the other plugins are kept when the plugin is disabled
+	assert.NoError(t, ensureConsolePluginEnabled(ctx, cli, testLogger, false))
+	assert.Equal(t, []string{"monitoring-plugin"}, getPlugins())
+
+	// the console capability is disabled
+	cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+	assert.NoError(t, ensureConsolePluginEnabled(ctx, cli, testLogger, true))
+}
+
+func TestGetConsoleOwner(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme()
+	utilruntime.Must(rbacv1.AddToScheme(scheme))
+
+	odfCsv := &opv1a1.ClusterServiceVersion{}
+	odfCsv.Name = "odf-operator.v4.21.0"
+	odfCsv.Namespace = OperatorNamespace
+
+	newClusterRole := func(name, csvName string) *rbacv1.ClusterRole {
+		clusterRole := &rbacv1.ClusterRole{}
+		clusterRole.Name = name
+		clusterRole.UID = types.UID(name)
+		clusterRole.Labels = map[string]string{
+			OlmOwnerLabel:          csvName,
+			OlmOwnerKindLabel:      opv1a1.ClusterServiceVersionKind,
+			OlmOwnerNamespaceLabel: OperatorNamespace,
+		}
+		return clusterRole
+	}
+
+	// without OLM there is no owner
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	owner, err := getConsoleOwner(ctx, cli, odfCsv)
+	assert.NoError(t, err)
+	assert.Nil(t, owner)
+
+	previousOwner := newClusterRole("odf-operator.v4.20.0-a", "odf-operator.v4.20.0")
+	cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		previousOwner,
+		newClusterRole("odf-operator.v4.21.0-b", odfCsv.Name),
+		newClusterRole("odf-operator.v4.21.0-a", odfCsv.Name),
+	).Build()
+	owner, err = getConsoleOwner(ctx, cli, odfCsv)
+	assert.NoError(t, err)
+	if assert.NotNil(t, owner) {
+		assert.Equal(t, "odf-operator.v4.21.0-a", owner.Name)
+	}
+
+	// the owner of the previous csv is kept so that an upgrade does not delete the console resources
+	cqs := &consolev1.ConsoleQuickStart{}
+	assert.NoError(t, setConsoleOwner(previousOwner, cqs, cli.Scheme()))
+	assert.NoError(t, setConsoleOwner(owner, cqs, cli.Scheme()))
+	assert.NoError(t, setConsoleOwner(nil, cqs, cli.Scheme()))
+	if assert.Len(t, cqs.OwnerReferences, 2) {
+		assert.Equal(t, previousOwner.Name, cqs.OwnerReferences[0].Name)
+		assert.Equal(t, owner.Name, cqs.OwnerReferences[1].Name)
+		assert.Nil(t, cqs.OwnerReferences[1].Controller)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/red-hat-storage/odf-operator/console"
+	"github.com/red-hat-storage/odf-operator/pkg/util"
+)
+
+const (
+	noobaaCRDName    = "noobaas.noobaa.io"
+	noobaaApiVersion = "noobaa.io/v1alpha1"
+	noobaaKind       = "NooBaa"
+)
+
+// componentKind is a kind whose resources in the operator namespace decide which console resources are desired
+type componentKind struct {
+	crdName    string
+	apiVersion string
+	kind       string
+}
+
+// componentKinds are watched for creation and deletion once their CRD exists
+var componentKinds = []componentKind{
+	{crdName: noobaaCRDName, apiVersion: noobaaApiVersion, kind: noobaaKind},
+	{crdName: storageClusterCRDName, apiVersion: storageClusterApiVersion, kind: storageClusterKind},
+	{crdName: storageClientCRDName, apiVersion: storageClientApiVersion, kind: storageClientKind},
+}
+
+// GetConsolePluginProxyConditions returns the conditions of the console plugin proxy entries met by the cluster
+func GetConsolePluginProxyConditions(ctx context.Context, cli client.Client) (map[console.ConsolePluginProxyCondition]bool, error) {
+
+	conditions := map[console.ConsolePluginProxyCondition]bool{}
+
+	isROSA, err := util.IsROSACluster(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	conditions[console.ProxyConditionROSA] = isROSA
+
+	hasNooBaa, err := hasComponentResource(ctx, cli, noobaaApiVersion, noobaaKind)
+	if err != nil {
+		return nil, err
+	}
+	conditions[console.ProxyConditionNooBaa] = hasNooBaa
+
+	return conditions, nil
+}
+
+// hasComponentResource returns true if a resource of the kind exists in the operator namespace,
+// it returns false if the kind is not installed
+func hasComponentResource(ctx context.Context, cli client.Client, apiVersion, kind string) (bool, error) {
+
+	crList := &metav1.PartialObjectMetadataList{}
+	crList.APIVersion = apiVersion
+	crList.Kind = kind
+	if err := cli.List(ctx, crList, client.InNamespace(OperatorNamespace), client.Limit(1)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(crList.Items) > 0, nil
+}
+
+// ensureComponentWatches watches the creation and deletion of the component kinds once their CRD exists,
+// e.g. the s3 and iam proxy entries of the console plugin are only registered while a NooBaa CR exists.
+func (r *ClusterVersionReconciler) ensureComponentWatches(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	if r.componentWatches == nil {
+		r.componentWatches = map[string]bool{}
+	}
+
+	for _, component := range componentKinds {
+		if r.componentWatches[component.kind] {
+			continue
+		}
+
+		crd := &extv1.CustomResourceDefinition{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: component.crdName}, crd); err != nil {
+			logger.Info("CRD not available yet, will retry on next reconcile", "crd", component.crdName)
+			continue
+		}
+
+		obj := &metav1.PartialObjectMetadata{}
+		obj.APIVersion = component.apiVersion
+		obj.Kind = component.kind
+
+		if err := r.controller.Watch(
+			source.Kind(
+				r.cache,
+				client.Object(obj),
+				&handler.EnqueueRequestForObject{},
+				predicate.Funcs{
+					CreateFunc: func(e event.CreateEvent) bool {
+						return e.Object.GetNamespace() == OperatorNamespace
+					},
+					DeleteFunc: func(e event.DeleteEvent) bool {
+						return e.Object.GetNamespace() == OperatorNamespace
+					},
+					UpdateFunc: func(e event.UpdateEvent) bool {
+						return false
+					},
+					GenericFunc: func(e event.GenericEvent) bool {
+						return false
+					},
+				},
+			),
+		); err != nil {
+			logger.Error(err, "Failed to add dynamic watch", "kind", component.kind)
+			continue
+		}
+		r.componentWatches[component.kind] = true
+		logger.Info("Dynamic watch added", "kind", component.kind)
+	}
+}
+
+// isComponentCRD returns true if the CRD is the CRD of a component kind
+func isComponentCRD(name string) bool {
+	for _, component := range componentKinds {
+		if component.crdName == name {
+			return true
+		}
+	}
+	return false
+}
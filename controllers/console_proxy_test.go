@@ -0,0 +1,66 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/red-hat-storage/odf-operator/console"
+)
+
+func TestGetConsolePluginProxyConditions(t *testing.T) {
+	scheme := newTestScheme()
+	utilruntime.Must(configv1.AddToScheme(scheme))
+	noobaaGVK := schema.FromAPIVersionAndKind(noobaaApiVersion, noobaaKind)
+	scheme.AddKnownTypeWithName(noobaaGVK, &metav1.PartialObjectMetadata{})
+	scheme.AddKnownTypeWithName(noobaaGVK.GroupVersion().WithKind(noobaaKind+"List"), &metav1.PartialObjectMetadataList{})
+
+	infrastructure := &configv1.Infrastructure{}
+	infrastructure.Name = "cluster"
+	infrastructure.Status.PlatformStatus = &configv1.PlatformStatus{
+		Type: configv1.AWSPlatformType,
+		AWS: &configv1.AWSPlatformStatus{
+			ResourceTags: []configv1.AWSResourceTag{{Key: "red-hat-clustertype", Value: "rosa"}},
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(infrastructure).Build()
+	conditions, err := GetConsolePluginProxyConditions(context.Background(), cli)
+	assert.NoError(t, err)
+	assert.True(t, conditions[console.ProxyConditionROSA])
+	assert.False(t, conditions[console.ProxyConditionNooBaa])
+
+	noobaa := &metav1.PartialObjectMetadata{}
+	noobaa.SetGroupVersionKind(noobaaGVK)
+	noobaa.Name = "noobaa"
+	noobaa.Namespace = OperatorNamespace
+
+	infrastructure.Status.PlatformStatus.AWS.ResourceTags = nil
+	cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(infrastructure, noobaa).Build()
+	conditions, err = GetConsolePluginProxyConditions(context.Background(), cli)
+	assert.NoError(t, err)
+	assert.False(t, conditions[console.ProxyConditionROSA])
+	assert.True(t, conditions[console.ProxyConditionNooBaa])
+}
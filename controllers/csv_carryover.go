@@ -0,0 +1,176 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"maps"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	CarryOverReplicas           = "spec.replicas"
+	CarryOverPodAnnotations     = "spec.template.metadata.annotations"
+	CarryOverHostNetwork        = "spec.template.spec.hostNetwork"
+	CarryOverDNSPolicy          = "spec.template.spec.dnsPolicy"
+	CarryOverNodeSelector       = "spec.template.spec.nodeSelector"
+	CarryOverTolerations        = "spec.template.spec.tolerations"
+	CarryOverAffinity           = "spec.template.spec.affinity"
+	CarryOverPriorityClassName  = "spec.template.spec.priorityClassName"
+	CarryOverContainerResources = "spec.template.spec.containers[*].resources"
+	CarryOverContainerEnv       = "spec.template.spec.containers[*].env"
+)
+
+var (
+	// DefaultCsvCarryOverFields are the fields carried over from the previous csv
+	// when the pkgs-config record does not list any carryOverFields.
+	DefaultCsvCarryOverFields = []string{
+		CarryOverReplicas,
+		CarryOverHostNetwork,
+		CarryOverDNSPolicy,
+	}
+
+	// csvCarryOverFuncs maps every supported carry-over path to the function
+	// copying it from the previous deployment spec into the new one.
+	csvCarryOverFuncs = map[string]func(prev, next *appsv1.DeploymentSpec){
+		CarryOverReplicas: func(prev, next *appsv1.DeploymentSpec) {
+			next.Replicas = prev.Replicas
+		},
+		CarryOverPodAnnotations: func(prev, next *appsv1.DeploymentSpec) {
+			if len(prev.Template.Annotations) == 0 {
+				return
+			}
+			if next.Template.Annotations == nil {
+				next.Template.Annotations = map[string]string{}
+			}
+			maps.Copy(next.Template.Annotations, prev.Template.Annotations)
+		},
+		CarryOverHostNetwork: func(prev, next *appsv1.DeploymentSpec) {
+			next.Template.Spec.HostNetwork = prev.Template.Spec.HostNetwork
+		},
+		CarryOverDNSPolicy: func(prev, next *appsv1.DeploymentSpec) {
+			next.Template.Spec.DNSPolicy = prev.Template.Spec.DNSPolicy
+		},
+		CarryOverNodeSelector: func(prev, next *appsv1.DeploymentSpec) {
+			next.Template.Spec.NodeSelector = maps.Clone(prev.Template.Spec.NodeSelector)
+		},
+		CarryOverTolerations: func(prev, next *appsv1.DeploymentSpec) {
+			next.Template.Spec.Tolerations = append([]corev1.Toleration(nil), prev.Template.Spec.Tolerations...)
+		},
+		CarryOverAffinity: func(prev, next *appsv1.DeploymentSpec) {
+			next.Template.Spec.Affinity = prev.Template.Spec.Affinity.DeepCopy()
+		},
+		CarryOverPriorityClassName: func(prev, next *appsv1.DeploymentSpec) {
+			next.Template.Spec.PriorityClassName = prev.Template.Spec.PriorityClassName
+		},
+		CarryOverContainerResources: func(prev, next *appsv1.DeploymentSpec) {
+			forEachMatchingContainer(prev, next, func(prevContainer, nextContainer *corev1.Container) {
+				prevContainer.Resources.DeepCopyInto(&nextContainer.Resources)
+			})
+		},
+		CarryOverContainerEnv: func(prev, next *appsv1.DeploymentSpec) {
+			forEachMatchingContainer(prev, next, func(prevContainer, nextContainer *corev1.Container) {
+				nextContainer.Env = getOverriddenEnvVars(nextContainer.Env, prevContainer.Env)
+			})
+		},
+	}
+)
+
+// IsValidCsvCarryOverField reports whether the path is a supported carry-over field.
+func IsValidCsvCarryOverField(path string) bool {
+	_, ok := csvCarryOverFuncs[path]
+	return ok
+}
+
+// GetCsvCarryOverFields returns the supported fields of the carryOverFields of a pkgs-config record. The
+// unsupported fields are logged and skipped, the defaults are returned when no supported field is listed.
+func GetCsvCarryOverFields(logger logr.Logger, key string, fields []string) []string {
+
+	carryOverFields := []string{}
+	for _, field := range fields {
+		if !IsValidCsvCarryOverField(field) {
+			logger.Info("skipping unsupported carry over field", "key", key, "field", field)
+			continue
+		}
+		carryOverFields = append(carryOverFields, field)
+	}
+
+	if len(carryOverFields) == 0 {
+		if len(fields) > 0 {
+			logger.Info("no supported carry over field, using the default fields", "key", key, "fields", DefaultCsvCarryOverFields)
+		}
+		return DefaultCsvCarryOverFields
+	}
+
+	return carryOverFields
+}
+
+// CarryOverDeploymentSpecFields copies the fields listed in paths from the previous
+// deployment spec into the new one. Unsupported paths are ignored.
+func CarryOverDeploymentSpecFields(prev, next *appsv1.DeploymentSpec, paths []string) {
+
+	for _, path := range paths {
+		if fn, ok := csvCarryOverFuncs[path]; ok {
+			fn(prev, next)
+		}
+	}
+}
+
+// forEachMatchingContainer calls fn for every container of next which has a container
+// with the same name in prev.
+func forEachMatchingContainer(prev, next *appsv1.DeploymentSpec, fn func(prevContainer, nextContainer *corev1.Container)) {
+
+	for i := range next.Template.Spec.Containers {
+		nextContainer := &next.Template.Spec.Containers[i]
+		for j := range prev.Template.Spec.Containers {
+			prevContainer := &prev.Template.Spec.Containers[j]
+			if prevContainer.Name == nextContainer.Name {
+				fn(prevContainer, nextContainer)
+				break
+			}
+		}
+	}
+}
+
+// getOverriddenEnvVars returns the env variables of envList1 with the values of envList2
+// applied on top, keeping the order of envList1 and appending the ones only in envList2.
+func getOverriddenEnvVars(envList1, envList2 []corev1.EnvVar) []corev1.EnvVar {
+
+	updatedEnvVars := make([]corev1.EnvVar, 0, len(envList1)+len(envList2))
+	for i := range envList1 {
+		updatedEnvVars = append(updatedEnvVars, *envList1[i].DeepCopy())
+	}
+
+	for i := range envList2 {
+		env := envList2[i].DeepCopy()
+		found := false
+		for j := range updatedEnvVars {
+			if updatedEnvVars[j].Name == env.Name {
+				updatedEnvVars[j] = *env
+				found = true
+				break
+			}
+		}
+		if !found {
+			updatedEnvVars = append(updatedEnvVars, *env)
+		}
+	}
+
+	return updatedEnvVars
+}
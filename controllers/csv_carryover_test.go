@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+func newCarryOverTestDeploymentSpec(replicas int32, cpu string, env ...corev1.EnvVar) *appsv1.DeploymentSpec {
+	return &appsv1.DeploymentSpec{
+		Replicas: ptr.To(replicas),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "manager",
+						Env:  env,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse(cpu),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCarryOverDeploymentSpecFields_Defaults(t *testing.T) {
+	prev := newCarryOverTestDeploymentSpec(1, "500m")
+	prev.Template.Spec.HostNetwork = true
+	prev.Template.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	prev.Template.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/infra": ""}
+
+	next := newCarryOverTestDeploymentSpec(0, "100m")
+
+	CarryOverDeploymentSpecFields(prev, next, DefaultCsvCarryOverFields)
+
+	assert.Equal(t, int32(1), *next.Replicas)
+	assert.True(t, next.Template.Spec.HostNetwork)
+	assert.Equal(t, corev1.DNSClusterFirstWithHostNet, next.Template.Spec.DNSPolicy)
+	// fields not listed are left untouched
+	assert.Nil(t, next.Template.Spec.NodeSelector)
+	cpu := next.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]
+	assert.Equal(t, "100m", cpu.String())
+}
+
+func TestCarryOverDeploymentSpecFields_ConfiguredFields(t *testing.T) {
+	prev := newCarryOverTestDeploymentSpec(1, "500m",
+		corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"},
+		corev1.EnvVar{Name: "ADMIN_ADDED", Value: "true"},
+	)
+	prev.Template.Annotations = map[string]string{"admin": "annotation"}
+	prev.Template.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/infra": ""}
+	prev.Template.Spec.Tolerations = []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists}}
+	prev.Template.Spec.PriorityClassName = "system-node-critical"
+
+	next := newCarryOverTestDeploymentSpec(0, "100m",
+		corev1.EnvVar{Name: "LOG_LEVEL", Value: "info"},
+		corev1.EnvVar{Name: "NEW_IN_RELEASE", Value: "1"},
+	)
+	next.Template.Annotations = map[string]string{"shipped": "annotation"}
+
+	CarryOverDeploymentSpecFields(prev, next, []string{
+		CarryOverPodAnnotations,
+		CarryOverNodeSelector,
+		CarryOverTolerations,
+		CarryOverPriorityClassName,
+		CarryOverContainerResources,
+		CarryOverContainerEnv,
+	})
+
+	assert.Equal(t, int32(0), *next.Replicas)
+	assert.Equal(t, map[string]string{"admin": "annotation", "shipped": "annotation"}, next.Template.Annotations)
+	assert.Equal(t, prev.Template.Spec.NodeSelector, next.Template.Spec.NodeSelector)
+	assert.Equal(t, prev.Template.Spec.Tolerations, next.Template.Spec.Tolerations)
+	assert.Equal(t, "system-node-critical", next.Template.Spec.PriorityClassName)
+	cpu := next.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]
+	assert.Equal(t, "500m", cpu.String())
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "NEW_IN_RELEASE", Value: "1"},
+		{Name: "ADMIN_ADDED", Value: "true"},
+	}, next.Template.Spec.Containers[0].Env)
+}
+
+func TestGetCsvCarryOverFields(t *testing.T) {
+	assert.Equal(t, DefaultCsvCarryOverFields, GetCsvCarryOverFields(testLogger, "ocs", nil))
+
+	fields := GetCsvCarryOverFields(testLogger, "ocs", []string{CarryOverTolerations, "spec.template.spec.volumes"})
+	assert.Equal(t, []string{CarryOverTolerations}, fields)
+
+	// none of the listed fields is supported, the defaults are carried over
+	fields = GetCsvCarryOverFields(testLogger, "ocs", []string{"spec.template.spec.volumes"})
+	assert.Equal(t, DefaultCsvCarryOverFields, fields)
+}
+
+func TestIsValidCsvCarryOverField(t *testing.T) {
+	for _, field := range DefaultCsvCarryOverFields {
+		assert.True(t, IsValidCsvCarryOverField(field))
+	}
+	assert.False(t, IsValidCsvCarryOverField("spec.template.spec.volumes"))
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// CsvScaleState is the scale state decided by the operator scaler for the deployments of a csv
+type CsvScaleState string
+
+const (
+	CsvScaledUp   CsvScaleState = "ScaledUp"
+	CsvScaledDown CsvScaleState = "ScaledDown"
+
+	// CsvScaleStateConfigMapName is the configmap in the operator namespace which records
+	// the desired scale state of the managed csvs, keyed by the csv name.
+	CsvScaleStateConfigMapName = "odf-operator-csv-scale-state"
+)
+
+// GetCsvScaleStates returns the desired scale state of the csvs recorded by the operator scaler.
+// An empty map is returned if nothing is recorded yet.
+func GetCsvScaleStates(ctx context.Context, cli client.Client) (map[string]CsvScaleState, error) {
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = CsvScaleStateConfigMapName
+	cm.Namespace = OperatorNamespace
+
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(cm), cm); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return map[string]CsvScaleState{}, nil
+		}
+		return nil, err
+	}
+
+	states := make(map[string]CsvScaleState, len(cm.Data))
+	for csvName, state := range cm.Data {
+		states[csvName] = CsvScaleState(state)
+	}
+
+	return states, nil
+}
+
+// SetCsvScaleState records the desired scale state of the csv. It has to be called
+// before the csv itself is updated so that the webhook accepts the new replicas.
+// The states of the csvs which no longer exist are pruned, except the states of the
+// csvs replaced by an existing csv, which are kept for the upgraded csv.
+func SetCsvScaleState(ctx context.Context, cli client.Client, csvName string, state CsvScaleState) error {
+
+	csvList := &opv1a1.ClusterServiceVersionList{}
+	if err := cli.List(ctx, csvList); err != nil {
+		return err
+	}
+	knownCsvs := map[string]bool{csvName: true}
+	for i := range csvList.Items {
+		knownCsvs[csvList.Items[i].Name] = true
+		if replaces := csvList.Items[i].Spec.Replaces; replaces != "" {
+			knownCsvs[replaces] = true
+		}
+	}
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = CsvScaleStateConfigMapName
+	cm.Namespace = OperatorNamespace
+
+	_, err := controllerutil.CreateOrUpdate(ctx, cli, cm, func() error {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		for name := range cm.Data {
+			if !knownCsvs[name] {
+				delete(cm.Data, name)
+			}
+		}
+		cm.Data[csvName] = string(state)
+		return nil
+	})
+
+	return err
+}
+
+// GetDesiredDeploymentReplicas returns the replica count of a scaled up deployment.
+func GetDesiredDeploymentReplicas(deploymentName string) int32 {
+
+	if replicaCount, ok := deploymentsWithNonDefaultReplicas[deploymentName]; ok {
+		return replicaCount
+	}
+
+	return 1
+}
+
+// ApplyCsvScaleState sets the replicas of the csv deployments according to the scale state
+// and returns true if the csv was changed.
+func ApplyCsvScaleState(csv *opv1a1.ClusterServiceVersion, state CsvScaleState) bool {
+
+	var changed bool
+	for i := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		deploymentName := csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[i].Name
+		deploymentSpec := &csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[i].Spec
+
+		switch state {
+		case CsvScaledUp:
+			if deploymentSpec.Replicas == nil || *deploymentSpec.Replicas < 1 {
+				deploymentSpec.Replicas = ptr.To(GetDesiredDeploymentReplicas(deploymentName))
+				changed = true
+			}
+		case CsvScaledDown:
+			if deploymentSpec.Replicas == nil || *deploymentSpec.Replicas != 0 {
+				deploymentSpec.Replicas = ptr.To(int32(0))
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newScaleStateTestCsv(replicas ...*int32) *opv1a1.ClusterServiceVersion {
+	csv := &opv1a1.ClusterServiceVersion{}
+	csv.Name = "rook-ceph-operator.v4.22.0"
+	for i, r := range replicas {
+		csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs = append(
+			csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs,
+			opv1a1.StrategyDeploymentSpec{
+				Name: []string{"rook-ceph-operator", "odf-external-snapshotter-operator"}[i],
+				Spec: appsv1.DeploymentSpec{Replicas: r},
+			},
+		)
+	}
+	return csv
+}
+
+func TestApplyCsvScaleState(t *testing.T) {
+	csv := newScaleStateTestCsv(ptr.To(int32(0)), nil)
+	assert.True(t, ApplyCsvScaleState(csv, CsvScaledUp))
+	assert.Equal(t, int32(1), *csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[0].Spec.Replicas)
+	assert.Equal(t, int32(2), *csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[1].Spec.Replicas)
+	assert.False(t, ApplyCsvScaleState(csv, CsvScaledUp))
+
+	assert.True(t, ApplyCsvScaleState(csv, CsvScaledDown))
+	for _, deployment := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+	}
+	assert.False(t, ApplyCsvScaleState(csv, CsvScaledDown))
+
+	// unknown states leave the csv untouched
+	csv = newScaleStateTestCsv(ptr.To(int32(3)))
+	assert.False(t, ApplyCsvScaleState(csv, CsvScaleState("")))
+	assert.Equal(t, int32(3), *csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[0].Spec.Replicas)
+}
+
+func TestCsvScaleStates(t *testing.T) {
+	ctx := context.Background()
+
+	newCsv := func(name, replaces string) *opv1a1.ClusterServiceVersion {
+		csv := newScaleStateTestCsv()
+		csv.Name = name
+		csv.Namespace = "openshift-storage"
+		csv.Spec.Replaces = replaces
+		return csv
+	}
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(newCsv("a.v1", ""), newCsv("b.v2", "b.v1")).Build()
+
+	states, err := GetCsvScaleStates(ctx, cli)
+	assert.NoError(t, err)
+	assert.Empty(t, states)
+
+	assert.NoError(t, SetCsvScaleState(ctx, cli, "a.v1", CsvScaledUp))
+	assert.NoError(t, SetCsvScaleState(ctx, cli, "b.v1", CsvScaledDown))
+
+	states, err = GetCsvScaleStates(ctx, cli)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]CsvScaleState{"a.v1": CsvScaledUp, "b.v1": CsvScaledDown}, states)
+
+	// the state of a csv which no longer exists is pruned, the state of the replaced csv is kept
+	assert.NoError(t, cli.Delete(ctx, newCsv("a.v1", "")))
+	assert.NoError(t, SetCsvScaleState(ctx, cli, "b.v2", CsvScaledUp))
+
+	states, err = GetCsvScaleStates(ctx, cli)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]CsvScaleState{"b.v1": CsvScaledDown, "b.v2": CsvScaledUp}, states)
+}
+
+func TestReconcileIdleOperators(t *testing.T) {
+	ctx := context.Background()
+
+	newCsv := func(name, replaces string) *opv1a1.ClusterServiceVersion {
+		csv := newScaleStateTestCsv(nil)
+		csv.Name = name
+		csv.Namespace = "openshift-storage"
+		csv.Spec.Replaces = replaces
+		return csv
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(
+		newCsv("idle.v2", ""),
+		newCsv("in-use.v2", ""),
+		newCsv("recorded.v2", ""),
+		newCsv("upgraded.v2", "upgraded.v1"),
+		newCsv("unscaled.v2", ""),
+	).Build()
+	assert.NoError(t, SetCsvScaleState(ctx, cli, "recorded.v2", CsvScaledUp))
+	assert.NoError(t, SetCsvScaleState(ctx, cli, "upgraded.v1", CsvScaledUp))
+
+	r := &OperatorScalerReconciler{Client: cli}
+	record := OdfOperatorConfigMapRecord{
+		Namespace:           "openshift-storage",
+		ScaleUpOnInstanceOf: []string{"storageclusters.ocs.openshift.io"},
+	}
+	managedCsvs := map[string]OdfOperatorConfigMapRecord{
+		"idle.v2":     record,
+		"in-use.v2":   record,
+		"recorded.v2": record,
+		"upgraded.v2": record,
+		"missing.v2":  record,
+		"unscaled.v2": {Namespace: "openshift-storage"},
+	}
+	err := r.reconcileIdleOperators(ctx, testLogger, managedCsvs, map[string]bool{"in-use.v2": true})
+	assert.NoError(t, err)
+
+	getReplicas := func(name string) *int32 {
+		csv := &opv1a1.ClusterServiceVersion{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: name, Namespace: "openshift-storage"}, csv))
+		return csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[0].Spec.Replicas
+	}
+	assert.Equal(t, ptr.To(int32(0)), getReplicas("idle.v2"))
+	assert.Nil(t, getReplicas("in-use.v2"))
+	assert.Nil(t, getReplicas("recorded.v2"))
+	assert.Equal(t, ptr.To(int32(1)), getReplicas("upgraded.v2"))
+	assert.Nil(t, getReplicas("unscaled.v2"))
+
+	states, err := GetCsvScaleStates(ctx, cli)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]CsvScaleState{
+		"idle.v2":     CsvScaledDown,
+		"recorded.v2": CsvScaledUp,
+		"upgraded.v1": CsvScaledUp,
+		"upgraded.v2": CsvScaledUp,
+	}, states)
+}
@@ -40,7 +40,9 @@ var (
 	odfOperatorConfigMapName string
 )
 
-func init() {
+// LoadEnv reads the operator namespace and the name of the pkgs configmap from the env, it panics if one is not set.
+// It is called before the package is used, the tests call it once they set the env.
+func LoadEnv() {
 	OperatorNamespace = GetEnvOrPanic("OPERATOR_NAMESPACE")
 	odfOperatorConfigMapName = GetEnvOrPanic("PKGS_CONFIG_MAP_NAME")
 }
@@ -0,0 +1,175 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// HandoffAnnotationKey is set by the operator releasing a resource to the name of the operator taking it over
+	HandoffAnnotationKey = "odf.openshift.io/handoff-to"
+	// OwnedByAnnotationKey is set to the name of the operator which took over a resource
+	OwnedByAnnotationKey = "odf.openshift.io/owned-by"
+
+	// HandoverOperatorName is the name of this operator in the handover annotations
+	HandoverOperatorName = "odf-operator"
+
+	// handoverRetryInterval is the time after which a resource which is not released yet is checked again
+	handoverRetryInterval = 30 * time.Second
+)
+
+// WaitingForReleaseError is returned when a resource is still controlled by another owner, which has not released it
+type WaitingForReleaseError struct {
+	Object string
+	Holder string
+}
+
+func (e *WaitingForReleaseError) Error() string {
+	return fmt.Sprintf("%s is controlled by %s, waiting for it to be released", e.Object, e.Holder)
+}
+
+// IsWaitingForRelease returns true if the error is a WaitingForReleaseError
+func IsWaitingForRelease(err error) bool {
+	var waitingErr *WaitingForReleaseError
+	return errors.As(err, &waitingErr)
+}
+
+// Handover takes over the resources migrating from other operators to the owner, without coupling the versions of the
+// operators. A resource is taken over once one of the following holds, it is left untouched otherwise:
+//   - it does not exist, or it is already owned by the owner
+//   - it is released by the previous operator with the handoff annotation
+//   - it has no controller, or its controller no longer exists, e.g. the CSV of the previous operator was replaced
+//   - its controller is an earlier CSV of the package of the owner CSV, which is still deleted during an upgrade
+type Handover struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	Owner  client.Object
+}
+
+// Acquire returns nil if the resource can be reconciled by the owner, or a WaitingForReleaseError if it is not released
+// yet. Only the name and namespace of the object are read, the object itself is left unchanged.
+func (h *Handover) Acquire(ctx context.Context, obj client.Object) error {
+
+	gvk, err := apiutil.GVKForObject(obj, h.Scheme)
+	if err != nil {
+		return err
+	}
+
+	current := &metav1.PartialObjectMetadata{}
+	current.SetGroupVersionKind(gvk)
+	if err := h.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	annotations := current.GetAnnotations()
+	if annotations[OwnedByAnnotationKey] == HandoverOperatorName || annotations[HandoffAnnotationKey] == HandoverOperatorName {
+		return nil
+	}
+
+	controllerRef := metav1.GetControllerOf(current)
+	if controllerRef == nil || controllerRef.UID == h.Owner.GetUID() || h.replaces(controllerRef) {
+		return nil
+	}
+
+	holder := &metav1.PartialObjectMetadata{}
+	holder.APIVersion = controllerRef.APIVersion
+	holder.Kind = controllerRef.Kind
+	holder.Name = controllerRef.Name
+	holder.Namespace = obj.GetNamespace()
+	if err := h.Client.Get(ctx, client.ObjectKeyFromObject(holder), holder); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if holder.UID != controllerRef.UID {
+		// the controller was replaced by a new object of the same name
+		return nil
+	}
+
+	return &WaitingForReleaseError{
+		Object: fmt.Sprintf("%s %s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName()),
+		Holder: fmt.Sprintf("%s %s", controllerRef.Kind, controllerRef.Name),
+	}
+}
+
+// replaces returns true if the owner is a CSV upgrading the CSV of the controller reference, an earlier version of the
+// same package. odf-operator is upgraded with olm.skipRange, which leaves spec.replaces empty, so the version of the
+// controller is also read from its CSV name, e.g. odf-operator.v4.21.0, and compared to the version of the owner.
+func (h *Handover) replaces(controllerRef *metav1.OwnerReference) bool {
+
+	ownerCsv, ok := h.Owner.(*opv1a1.ClusterServiceVersion)
+	if !ok {
+		return false
+	}
+
+	if controllerRef.APIVersion != opv1a1.SchemeGroupVersion.String() || controllerRef.Kind != opv1a1.ClusterServiceVersionKind {
+		return false
+	}
+
+	ownerPackage, _, _ := strings.Cut(ownerCsv.Name, ".")
+	holderPackage, holderVersion, _ := strings.Cut(controllerRef.Name, ".")
+	if holderPackage != ownerPackage {
+		return false
+	}
+	if controllerRef.Name == ownerCsv.Spec.Replaces {
+		return true
+	}
+
+	version, err := semver.ParseTolerant(holderVersion)
+	return err == nil && version.LT(ownerCsv.Spec.Version.Version)
+}
+
+// Claim makes the owner the controller of an acquired resource, it is called in the mutate function of CreateOrUpdate.
+// The controller reference of the previous operator is swapped for the owner, the other owner references are kept.
+func (h *Handover) Claim(obj client.Object) error {
+
+	ownerRefs := obj.GetOwnerReferences()
+	kept := ownerRefs[:0]
+	for _, ref := range ownerRefs {
+		if ref.Controller != nil && *ref.Controller && ref.UID != h.Owner.GetUID() {
+			continue
+		}
+		kept = append(kept, ref)
+	}
+	obj.SetOwnerReferences(kept)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	delete(annotations, HandoffAnnotationKey)
+	annotations[OwnedByAnnotationKey] = HandoverOperatorName
+	obj.SetAnnotations(annotations)
+
+	return controllerutil.SetControllerReference(h.Owner, obj, h.Scheme)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/api/pkg/lib/version"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestHandoverCsv(name string, uid types.UID) *opv1a1.ClusterServiceVersion {
+	csv := &opv1a1.ClusterServiceVersion{}
+	csv.Name = name
+	csv.Namespace = OperatorNamespace
+	csv.UID = uid
+	_, csvVersion, _ := strings.Cut(name, ".")
+	csv.Spec.Version = version.OperatorVersion{Version: semver.MustParse(strings.TrimPrefix(csvVersion, "v"))}
+	return csv
+}
+
+func newTestHandoverSecret(holder *opv1a1.ClusterServiceVersion, annotations map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	secret.Name = "ux-backend-proxy"
+	secret.Namespace = OperatorNamespace
+	secret.Annotations = annotations
+	secret.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "operators.coreos.com/v1alpha1",
+		Kind:       "ClusterServiceVersion",
+		Name:       holder.Name,
+		UID:        holder.UID,
+		Controller: ptr.To(true),
+	}}
+	return secret
+}
+
+func TestHandoverAcquire(t *testing.T) {
+	odfCsv := newTestHandoverCsv("odf-operator.v4.22.0", "odf-uid")
+	odfCsv.Spec.Replaces = "odf-operator.v4.21.0"
+	replacedOdfCsv := newTestHandoverCsv("odf-operator.v4.21.0", "replaced-odf-uid")
+	olderOdfCsv := newTestHandoverCsv("odf-operator.v4.20.0", "older-odf-uid")
+	newerOdfCsv := newTestHandoverCsv("odf-operator.v4.23.0", "newer-odf-uid")
+	// the upgrades with olm.skipRange leave spec.replaces empty
+	skipRangeOdfCsv := newTestHandoverCsv("odf-operator.v4.22.0", "odf-uid")
+	ocsCsv := newTestHandoverCsv("ocs-operator.v4.21.0", "ocs-uid")
+
+	testCases := []struct {
+		label   string
+		owner   *opv1a1.ClusterServiceVersion
+		objects []client.Object
+		waiting bool
+	}{
+		{
+			label:   "resource does not exist",
+			objects: []client.Object{odfCsv},
+		},
+		{
+			label:   "resource is held by a live controller",
+			objects: []client.Object{odfCsv, ocsCsv, newTestHandoverSecret(ocsCsv, nil)},
+			waiting: true,
+		},
+		{
+			label: "resource is released with the handoff annotation",
+			objects: []client.Object{odfCsv, ocsCsv,
+				newTestHandoverSecret(ocsCsv, map[string]string{HandoffAnnotationKey: HandoverOperatorName})},
+		},
+		{
+			label: "resource is handed off to another operator",
+			objects: []client.Object{odfCsv, ocsCsv,
+				newTestHandoverSecret(ocsCsv, map[string]string{HandoffAnnotationKey: "other-operator"})},
+			waiting: true,
+		},
+		{
+			label:   "controller no longer exists",
+			objects: []client.Object{odfCsv, newTestHandoverSecret(ocsCsv, nil)},
+		},
+		{
+			label: "controller was replaced by an object of the same name",
+			objects: []client.Object{odfCsv, newTestHandoverCsv(ocsCsv.Name, "new-ocs-uid"),
+				newTestHandoverSecret(ocsCsv, nil)},
+		},
+		{
+			label:   "controlled by the replaced odf CSV",
+			objects: []client.Object{odfCsv, replacedOdfCsv, newTestHandoverSecret(replacedOdfCsv, nil)},
+		},
+		{
+			label:   "controlled by an earlier odf CSV which is not replaced",
+			objects: []client.Object{odfCsv, olderOdfCsv, newTestHandoverSecret(olderOdfCsv, nil)},
+		},
+		{
+			label:   "controlled by an earlier odf CSV on an upgrade without replaces",
+			owner:   skipRangeOdfCsv,
+			objects: []client.Object{skipRangeOdfCsv, replacedOdfCsv, newTestHandoverSecret(replacedOdfCsv, nil)},
+		},
+		{
+			label:   "controlled by a newer odf CSV",
+			objects: []client.Object{odfCsv, newerOdfCsv, newTestHandoverSecret(newerOdfCsv, nil)},
+			waiting: true,
+		},
+		{
+			label:   "resource is already owned",
+			objects: []client.Object{odfCsv, newTestHandoverSecret(odfCsv, nil)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			owner := odfCsv
+			if tc.owner != nil {
+				owner = tc.owner
+			}
+			handover := &Handover{
+				Client: fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(tc.objects...).Build(),
+				Scheme: newTestScheme(),
+				Owner:  owner,
+			}
+
+			obj := getUXBackendServerSecret()
+			err := handover.Acquire(testContext, obj)
+			assert.Equal(t, tc.waiting, IsWaitingForRelease(err), "unexpected error: %v", err)
+			if !tc.waiting {
+				assert.NoError(t, err)
+			}
+			// the desired object is not overwritten
+			assert.Empty(t, obj.OwnerReferences)
+		})
+	}
+}
+
+func TestHandoverClaim(t *testing.T) {
+	odfCsv := newTestHandoverCsv("odf-operator.v4.22.0", "odf-uid")
+	ocsCsv := newTestHandoverCsv("ocs-operator.v4.21.0", "ocs-uid")
+
+	secret := newTestHandoverSecret(ocsCsv, map[string]string{HandoffAnnotationKey: HandoverOperatorName})
+	secret.OwnerReferences = append(secret.OwnerReferences, metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       "other",
+		UID:        "other-uid",
+	})
+
+	handover := &Handover{Scheme: newTestScheme(), Owner: odfCsv}
+	assert.NoError(t, handover.Claim(secret))
+
+	assert.Len(t, secret.OwnerReferences, 2)
+	assert.Equal(t, types.UID("other-uid"), secret.OwnerReferences[0].UID)
+	assert.Equal(t, odfCsv.UID, metav1.GetControllerOf(secret).UID)
+	assert.NotContains(t, secret.Annotations, HandoffAnnotationKey)
+	assert.Equal(t, HandoverOperatorName, secret.Annotations[OwnedByAnnotationKey])
+
+	// claiming again is a no-op
+	assert.NoError(t, handover.Claim(secret))
+	assert.Len(t, secret.OwnerReferences, 2)
+}
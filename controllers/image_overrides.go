@@ -0,0 +1,183 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ImageOverridesConfigMapName is the optional configmap in the operator namespace
+	// holding the hotfix images of the managed csvs, one ImageOverride per data key.
+	ImageOverridesConfigMapName = "odf-operator-image-overrides"
+)
+
+type ImageOverride struct {
+	/* examples
+	   pkg: rook-ceph-operator
+	   image: registry.redhat.io/odf4/rook-ceph-rhel9-operator@sha256:aaa
+	   replacement: quay.io/rhceph-dev/rook-ceph@sha256:bbb
+	   ---------------------------------------
+	   csv: mcg-operator.v4.20.1
+	   container: noobaa-operator
+	   replacement: quay.io/rhceph-dev/mcg-operator@sha256:ccc
+	*/
+
+	// Pkg restricts the override to the csv of the package
+	Pkg string `yaml:"pkg"`
+	// Csv pins the override to a single csv, it stops applying once the csv is replaced
+	Csv string `yaml:"csv"`
+	// Image is the image being replaced, an override matching by image stops applying
+	// once a csv ships a different image
+	Image string `yaml:"image"`
+	// Container restricts the override to the containers with the name, it requires Csv
+	// when Image is not set
+	Container string `yaml:"container"`
+	// Replacement is the hotfix image
+	Replacement string `yaml:"replacement"`
+}
+
+// Validate returns an error if the override could apply to more than intended or never expire.
+func (o *ImageOverride) Validate() error {
+
+	if o.Replacement == "" {
+		return fmt.Errorf("replacement is required")
+	}
+
+	if o.Image == "" && o.Container == "" {
+		return fmt.Errorf("one of image or container is required")
+	}
+
+	if o.Image == "" && o.Csv == "" {
+		return fmt.Errorf("csv is required when image is not set")
+	}
+
+	return nil
+}
+
+// GetImageOverrides returns the valid overrides from the image overrides configmap sorted by their key.
+// Invalid entries are logged and skipped, no overrides are returned if the configmap is not found.
+func GetImageOverrides(ctx context.Context, cli client.Client, logger logr.Logger) ([]ImageOverride, error) {
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = ImageOverridesConfigMapName
+	cm.Namespace = OperatorNamespace
+
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(cm), cm); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil, nil
+		}
+		logger.Error(err, "failed to get configmap", "configmap", cm.Name)
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	overrides := []ImageOverride{}
+	for _, key := range keys {
+		override := ImageOverride{}
+		if err := yaml.Unmarshal([]byte(cm.Data[key]), &override); err != nil {
+			logger.Error(err, "failed to unmarshal image override", "key", key)
+			continue
+		}
+		if err := override.Validate(); err != nil {
+			logger.Error(err, "skipping invalid image override", "key", key)
+			continue
+		}
+		overrides = append(overrides, override)
+	}
+
+	return overrides, nil
+}
+
+// ApplyImageOverrides replaces the images of the csv deployments and its relatedImages according
+// to the overrides matching the csv of the package, and returns true if the csv was changed.
+func ApplyImageOverrides(csv *opv1a1.ClusterServiceVersion, pkg string, overrides []ImageOverride) bool {
+
+	var changed bool
+	for i := range overrides {
+		override := &overrides[i]
+
+		if (override.Pkg != "" && override.Pkg != pkg) || (override.Csv != "" && override.Csv != csv.Name) {
+			continue
+		}
+
+		// images replaced by this override, used to update the relatedImages
+		replacedImages := map[string]bool{}
+		if override.Image != "" {
+			replacedImages[override.Image] = true
+		}
+
+		replaceImage := func(container *corev1.Container) {
+			if override.Container != "" && container.Name != override.Container {
+				return
+			}
+			if override.Image != "" && container.Image != override.Image {
+				return
+			}
+			if container.Image != override.Replacement {
+				replacedImages[container.Image] = true
+				container.Image = override.Replacement
+				changed = true
+			}
+		}
+
+		for j := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+			podSpec := &csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[j].Spec.Template.Spec
+			for k := range podSpec.InitContainers {
+				replaceImage(&podSpec.InitContainers[k])
+			}
+			for k := range podSpec.Containers {
+				replaceImage(&podSpec.Containers[k])
+
+				// operand images are commonly passed to the operators as env vars
+				if override.Image == "" {
+					continue
+				}
+				for l := range podSpec.Containers[k].Env {
+					env := &podSpec.Containers[k].Env[l]
+					if env.Value == override.Image {
+						env.Value = override.Replacement
+						changed = true
+					}
+				}
+			}
+		}
+
+		for j := range csv.Spec.RelatedImages {
+			relatedImage := &csv.Spec.RelatedImages[j]
+			if replacedImages[relatedImage.Image] {
+				relatedImage.Image = override.Replacement
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newImageOverrideTestCsv() *opv1a1.ClusterServiceVersion {
+	csv := &opv1a1.ClusterServiceVersion{}
+	csv.Name = "rook-ceph-operator.v4.22.0"
+	csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs = []opv1a1.StrategyDeploymentSpec{{Name: "rook-ceph-operator"}}
+
+	podSpec := &csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[0].Spec.Template.Spec
+	podSpec.Containers = []corev1.Container{{
+		Name:  "rook-ceph-operator",
+		Image: "registry/rook:1",
+		Env:   []corev1.EnvVar{{Name: "ROOK_CSI_CEPH_IMAGE", Value: "registry/ceph:1"}},
+	}}
+	csv.Spec.RelatedImages = []opv1a1.RelatedImage{
+		{Name: "rook", Image: "registry/rook:1"},
+		{Name: "ceph", Image: "registry/ceph:1"},
+	}
+
+	return csv
+}
+
+func TestApplyImageOverrides(t *testing.T) {
+	cases := []struct {
+		label         string
+		pkg           string
+		override      ImageOverride
+		expectChanged bool
+		expectRook    string
+		expectCeph    string
+	}{
+		{
+			label:         "match by image",
+			pkg:           "rook-ceph-operator",
+			override:      ImageOverride{Image: "registry/rook:1", Replacement: "hotfix/rook:1"},
+			expectChanged: true,
+			expectRook:    "hotfix/rook:1",
+			expectCeph:    "registry/ceph:1",
+		},
+		{
+			label:         "match operand image passed by env",
+			pkg:           "rook-ceph-operator",
+			override:      ImageOverride{Pkg: "rook-ceph-operator", Image: "registry/ceph:1", Replacement: "hotfix/ceph:1"},
+			expectChanged: true,
+			expectRook:    "registry/rook:1",
+			expectCeph:    "hotfix/ceph:1",
+		},
+		{
+			label:         "match by container of the pinned csv",
+			pkg:           "rook-ceph-operator",
+			override:      ImageOverride{Csv: "rook-ceph-operator.v4.22.0", Container: "rook-ceph-operator", Replacement: "hotfix/rook:1"},
+			expectChanged: true,
+			expectRook:    "hotfix/rook:1",
+			expectCeph:    "registry/ceph:1",
+		},
+		{
+			label:      "pinned to the previous csv",
+			pkg:        "rook-ceph-operator",
+			override:   ImageOverride{Csv: "rook-ceph-operator.v4.21.0", Container: "rook-ceph-operator", Replacement: "hotfix/rook:1"},
+			expectRook: "registry/rook:1",
+			expectCeph: "registry/ceph:1",
+		},
+		{
+			label:      "image shipped by the csv differs",
+			pkg:        "rook-ceph-operator",
+			override:   ImageOverride{Image: "registry/rook:0", Replacement: "hotfix/rook:0"},
+			expectRook: "registry/rook:1",
+			expectCeph: "registry/ceph:1",
+		},
+		{
+			label:      "other package",
+			pkg:        "rook-ceph-operator",
+			override:   ImageOverride{Pkg: "mcg-operator", Image: "registry/rook:1", Replacement: "hotfix/rook:1"},
+			expectRook: "registry/rook:1",
+			expectCeph: "registry/ceph:1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			csv := newImageOverrideTestCsv()
+			overrides := []ImageOverride{c.override}
+
+			assert.Equal(t, c.expectChanged, ApplyImageOverrides(csv, c.pkg, overrides))
+
+			container := csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[0].Spec.Template.Spec.Containers[0]
+			assert.Equal(t, c.expectRook, container.Image)
+			assert.Equal(t, c.expectCeph, container.Env[0].Value)
+			assert.Equal(t, c.expectRook, csv.Spec.RelatedImages[0].Image)
+			assert.Equal(t, c.expectCeph, csv.Spec.RelatedImages[1].Image)
+
+			// applying again is a no-op
+			assert.False(t, ApplyImageOverrides(csv, c.pkg, overrides))
+		})
+	}
+}
+
+func TestGetImageOverrides(t *testing.T) {
+	ctx := context.Background()
+
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	overrides, err := GetImageOverrides(ctx, cli, testLogger)
+	assert.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = ImageOverridesConfigMapName
+	cm.Namespace = OperatorNamespace
+	cm.Data = map[string]string{
+		"b-rook":        "image: registry/rook:1\nreplacement: hotfix/rook:1\n",
+		"a-noobaa":      "csv: mcg-operator.v4.22.0\ncontainer: noobaa-operator\nreplacement: hotfix/noobaa:1\n",
+		"no-pin":        "container: noobaa-operator\nreplacement: hotfix/noobaa:1\n",
+		"no-replace":    "image: registry/rook:1\n",
+		"not-a-mapping": "- a\n- b\n",
+	}
+
+	cli = fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(cm).Build()
+	overrides, err = GetImageOverrides(ctx, cli, testLogger)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImageOverride{
+		{Csv: "mcg-operator.v4.22.0", Container: "noobaa-operator", Replacement: "hotfix/noobaa:1"},
+		{Image: "registry/rook:1", Replacement: "hotfix/rook:1"},
+	}, overrides)
+}
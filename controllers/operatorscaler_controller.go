@@ -30,7 +30,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -96,7 +95,7 @@ type OperatorScalerReconciler struct {
 
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
-//+kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=get;list;update
+//+kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=get;list;watch;update
 
 //+kubebuilder:rbac:groups=ocs.openshift.io,resources=storageclusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=ceph.rook.io,resources=cephclusters,verbs=get;list;watch
@@ -115,8 +114,10 @@ func (r *OperatorScalerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	logger.Info("starting reconcile")
 
 	var kindMapping = map[string]*KindCsvsRecord{}
+	var managedCsvs = map[string]OdfOperatorConfigMapRecord{}
+	var csvsInUse = map[string]bool{}
 	var odfDepsCsvName = ""
-	if err := r.loadOdfConfigMapData(ctx, logger, kindMapping, &odfDepsCsvName); err != nil {
+	if err := r.loadOdfConfigMapData(ctx, logger, kindMapping, managedCsvs, &odfDepsCsvName); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -124,7 +125,15 @@ func (r *OperatorScalerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
-	if err := r.reconcileOperators(ctx, logger, kindMapping); err != nil {
+	if err := r.reconcileOperators(ctx, logger, kindMapping, csvsInUse); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileIdleOperators(ctx, logger, managedCsvs, csvsInUse); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileImageOverrides(ctx, logger, managedCsvs); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -140,7 +149,7 @@ func (r *OperatorScalerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ctrl.Result{}, nil
 }
 
-func (r *OperatorScalerReconciler) loadOdfConfigMapData(ctx context.Context, logger logr.Logger, kindMapping map[string]*KindCsvsRecord, odfDepsCsvName *string) error {
+func (r *OperatorScalerReconciler) loadOdfConfigMapData(ctx context.Context, logger logr.Logger, kindMapping map[string]*KindCsvsRecord, managedCsvs map[string]OdfOperatorConfigMapRecord, odfDepsCsvName *string) error {
 	logger.Info("entering loadOdfConfigMapData")
 
 	configmap, err := GetOdfConfigMap(ctx, r.Client, logger)
@@ -155,10 +164,15 @@ func (r *OperatorScalerReconciler) loadOdfConfigMapData(ctx context.Context, log
 			*odfDepsCsvName = record.Csv
 		}
 
+		if record.Csv != "" {
+			managedCsvs[record.Csv] = *record
+		}
+
 		if record.Csv == "" || record.ScaleUpOnInstanceOf == nil {
 			logger.Info("skipping the record from the configmap", "key", key, "value", rawValue)
 			return
 		}
+
 		for _, crdName := range record.ScaleUpOnInstanceOf {
 
 			rec, ok := kindMapping[crdName]
@@ -260,7 +274,7 @@ func (r *OperatorScalerReconciler) reconcileMetrics(ctx context.Context, logger
 	return combinedErr
 }
 
-func (r *OperatorScalerReconciler) reconcileOperators(ctx context.Context, logger logr.Logger, kindMapping map[string]*KindCsvsRecord) error {
+func (r *OperatorScalerReconciler) reconcileOperators(ctx context.Context, logger logr.Logger, kindMapping map[string]*KindCsvsRecord, csvsInUse map[string]bool) error {
 	logger.Info("entering reconcileOperators")
 
 	var returnErr error
@@ -281,6 +295,7 @@ func (r *OperatorScalerReconciler) reconcileOperators(ctx context.Context, logge
 		} else if len(crList.Items) > 0 {
 
 			for _, csvName := range resourceMapping.CsvNames {
+				csvsInUse[csvName] = true
 
 				csv := &opv1a1.ClusterServiceVersion{}
 				csv.Name = csvName
@@ -306,24 +321,131 @@ func (r *OperatorScalerReconciler) reconcileOperators(ctx context.Context, logge
 	return returnErr
 }
 
-func (r *OperatorScalerReconciler) updateCsvDeplymentsReplicas(ctx context.Context, logger logr.Logger, csv *opv1a1.ClusterServiceVersion) error {
+// reconcileIdleOperators scales down the managed csvs which have no instance of any of their
+// scaleUpOnInstanceOf kinds and no recorded scale state. Such csvs are normally scaled down by
+// the csv webhook on creation, this covers the csvs created while the webhook was not serving.
+// It must only be called once all the kinds were listed successfully by reconcileOperators.
+func (r *OperatorScalerReconciler) reconcileIdleOperators(ctx context.Context, logger logr.Logger, managedCsvs map[string]OdfOperatorConfigMapRecord, csvsInUse map[string]bool) error {
+	logger.Info("entering reconcileIdleOperators")
+
+	states, err := GetCsvScaleStates(ctx, r.Client)
+	if err != nil {
+		logger.Error(err, "failed getting csv scale states")
+		return err
+	}
+
+	var returnErr error
+
+	for csvName, record := range managedCsvs {
+
+		if record.ScaleUpOnInstanceOf == nil || csvsInUse[csvName] {
+			continue
+		}
+
+		// a recorded state is a decision already taken by the scaler, it is enforced by the webhook
+		if _, ok := states[csvName]; ok {
+			continue
+		}
 
-	var updateRequired bool
-	for i := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
-		deploymentName := csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[i].Name
-		deploymentSpec := &csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[i].Spec
-		if deploymentSpec.Replicas == nil || *deploymentSpec.Replicas < 1 {
-			// set default replica count
-			deploymentSpec.Replicas = ptr.To(int32(1))
-			// override default replica count if required
-			if replicaCount, ok := deploymentsWithNonDefaultReplicas[deploymentName]; ok {
-				deploymentSpec.Replicas = ptr.To(replicaCount)
+		csv := &opv1a1.ClusterServiceVersion{}
+		csv.Name = csvName
+		csv.Namespace = record.Namespace
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(csv), csv); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			logger.Error(err, "failed getting csv", "name", csvName)
+			multierr.AppendInto(&returnErr, err)
+			continue
+		}
+
+		// an upgraded csv keeps the scale up decision taken for the csv it replaces
+		if csv.Spec.Replaces != "" && states[csv.Spec.Replaces] == CsvScaledUp {
+			if err := r.updateCsvDeplymentsReplicas(ctx, logger, csv); err != nil {
+				multierr.AppendInto(&returnErr, err)
+			}
+			continue
+		}
+
+		if err := SetCsvScaleState(ctx, r.Client, csv.Name, CsvScaledDown); err != nil {
+			logger.Error(err, "failed recording csv scale state", "csvName", csv.Name)
+			multierr.AppendInto(&returnErr, err)
+			continue
+		}
+
+		if ApplyCsvScaleState(csv, CsvScaledDown) {
+			logger.Info("scaling down csv with running deployments and no instances", "csvName", csv.Name)
+			if err := r.Client.Update(ctx, csv); err != nil {
+				logger.Error(err, "failed updating csv replica", "csvName", csv.Name)
+				multierr.AppendInto(&returnErr, err)
+				continue
 			}
-			updateRequired = true
+			logger.Info("csv updated successfully", "csvName", csv.Name)
 		}
 	}
 
-	if updateRequired {
+	if returnErr == nil {
+		logger.Info("successfully completed reconcileIdleOperators")
+	}
+
+	return returnErr
+}
+
+// reconcileImageOverrides applies the image overrides to the existing managed csvs,
+// the csv webhook applies them to the csvs created or updated later.
+func (r *OperatorScalerReconciler) reconcileImageOverrides(ctx context.Context, logger logr.Logger, managedCsvs map[string]OdfOperatorConfigMapRecord) error {
+	logger.Info("entering reconcileImageOverrides")
+
+	imageOverrides, err := GetImageOverrides(ctx, r.Client, logger)
+	if err != nil {
+		return err
+	}
+
+	if len(imageOverrides) == 0 {
+		logger.Info("no image overrides found")
+		return nil
+	}
+
+	var returnErr error
+
+	for csvName, record := range managedCsvs {
+
+		csv := &opv1a1.ClusterServiceVersion{}
+		csv.Name = csvName
+		csv.Namespace = record.Namespace
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(csv), csv); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			logger.Error(err, "failed getting csv", "name", csvName)
+			multierr.AppendInto(&returnErr, err)
+			continue
+		}
+
+		if ApplyImageOverrides(csv, record.Pkg, imageOverrides) {
+			if err := r.Client.Update(ctx, csv); err != nil {
+				logger.Error(err, "failed updating csv images", "csvName", csv.Name)
+				multierr.AppendInto(&returnErr, err)
+				continue
+			}
+			logger.Info("csv images overridden successfully", "csvName", csv.Name)
+		}
+	}
+
+	if returnErr == nil {
+		logger.Info("successfully completed reconcileImageOverrides")
+	}
+
+	return returnErr
+}
+
+func (r *OperatorScalerReconciler) updateCsvDeplymentsReplicas(ctx context.Context, logger logr.Logger, csv *opv1a1.ClusterServiceVersion) error {
+
+	// record the decision first, the csv webhook repairs any update which conflicts with it
+	if err := SetCsvScaleState(ctx, r.Client, csv.Name, CsvScaledUp); err != nil {
+		logger.Error(err, "failed recording csv scale state", "csvName", csv.Name)
+		return err
+	}
+
+	if ApplyCsvScaleState(csv, CsvScaledUp) {
 		if err := r.Client.Update(ctx, csv); err != nil {
 			logger.Error(err, "failed updating csv replica", "csvName", csv.Name)
 			return err
@@ -388,6 +510,15 @@ func (r *OperatorScalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				predicate.GenerationChangedPredicate{},
 			),
 		).
+		Watches(
+			&corev1.ConfigMap{},
+			&handler.EnqueueRequestForObject{},
+			builder.WithPredicates(
+				predicate.NewPredicateFuncs(func(obj client.Object) bool {
+					return obj.GetName() == ImageOverridesConfigMapName && obj.GetNamespace() == r.OperatorNamespace
+				}),
+			),
+		).
 		// Watch CRDs so newly installed relevant CRDs can register dynamic CR watches.
 		Watches(
 			&extv1.CustomResourceDefinition{},
@@ -404,6 +535,18 @@ func (r *OperatorScalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				createOnlyPredicate,
 			),
 		).
+		// Watch CSVs so the ones created while the csv webhook was not serving are scaled down.
+		Watches(
+			&opv1a1.ClusterServiceVersion{},
+			&handler.EnqueueRequestForObject{},
+			builder.WithPredicates(
+				predicate.NewPredicateFuncs(func(obj client.Object) bool {
+					_, copied := obj.GetLabels()["olm.copiedFrom"]
+					return !copied
+				}),
+				createOnlyPredicate,
+			),
+		).
 		Build(r)
 
 	r.kindsBeingWatched = map[string]bool{}
@@ -18,36 +18,258 @@ package controllers
 
 import (
 	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/go-logr/logr"
 	consolev1 "github.com/openshift/api/console/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/yaml"
 )
 
-// ensureQuickStarts create or update the quickstarts
-func ensureQuickStarts(ctx context.Context, cli client.Client, logger logr.Logger) error {
-	for _, qs := range AllQuickStarts {
-		desiredCQS := &consolev1.ConsoleQuickStart{}
-		err := yaml.Unmarshal(qs, desiredCQS)
+const (
+	storageClusterCRDName    = "storageclusters.ocs.openshift.io"
+	storageClusterApiVersion = "ocs.openshift.io/v1"
+	storageClusterKind       = "StorageCluster"
+
+	storageClientCRDName    = "storageclients.ocs.openshift.io"
+	storageClientApiVersion = "ocs.openshift.io/v1alpha1"
+	storageClientKind       = "StorageClient"
+)
+
+const (
+	// QuickStartConditionsAnnotationKey lists the conditions which all have to be met for the quickstart to apply
+	QuickStartConditionsAnnotationKey = "odf.openshift.io/quickstart-conditions"
+	// QuickStartOCPVersionsAnnotationKey is the semver range of the OpenShift versions the quickstart applies to
+	QuickStartOCPVersionsAnnotationKey = "odf.openshift.io/quickstart-ocp-versions"
+
+	// The labels used by the console to pick the quickstart in the language of the user
+	quickStartLangLabelKey = "console.openshift.io/lang"
+	quickStartNameLabelKey = "console.openshift.io/name"
+	defaultQuickStartLang  = "en"
+)
+
+// The conditions of the quickstarts
+const (
+	QuickStartConditionInternal = "internal"
+	QuickStartConditionExternal = "external"
+	QuickStartConditionClient   = "client"
+	QuickStartConditionNooBaa   = "noobaa"
+)
+
+// quickStartsFS holds the quickstarts shipped by ODF. The quickstart <name>.yaml is the English quickstart
+// named <name>, its localized variants are named <name>.<lang>.yaml and inherit its conditions.
+//
+//go:embed quickstarts/*.yaml
+var quickStartsFS embed.FS
+
+// loadQuickStarts returns the quickstarts of the directory with their localized variants
+func loadQuickStarts(fsys fs.FS) ([]*consolev1.ConsoleQuickStart, error) {
+
+	files, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var quickStarts []*consolev1.ConsoleQuickStart
+	baseQuickStarts := map[string]*consolev1.ConsoleQuickStart{}
+	variants := map[string][]*consolev1.ConsoleQuickStart{}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		cqs := &consolev1.ConsoleQuickStart{}
+		if err := yaml.Unmarshal(data, cqs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal quickstart %s: %v", file, err)
+		}
+
+		name, lang, _ := strings.Cut(strings.TrimSuffix(path.Base(file), ".yaml"), ".")
+		if lang == "" {
+			lang = defaultQuickStartLang
+			if cqs.Name != name {
+				return nil, fmt.Errorf("quickstart %s is named %q, expected %q", file, cqs.Name, name)
+			}
+			baseQuickStarts[name] = cqs
+		} else {
+			cqs.Name = name + "-" + lang
+			variants[name] = append(variants[name], cqs)
+		}
+
+		if cqs.Labels == nil {
+			cqs.Labels = map[string]string{}
+		}
+		cqs.Labels[quickStartLangLabelKey] = lang
+		cqs.Labels[quickStartNameLabelKey] = name
+		cqs.Labels[managedByLabel] = ""
+
+		quickStarts = append(quickStarts, cqs)
+	}
+
+	for name, localized := range variants {
+		base, ok := baseQuickStarts[name]
+		if !ok {
+			return nil, fmt.Errorf("localized quickstarts of %q have no English quickstart", name)
+		}
+		for _, cqs := range localized {
+			cqs.Annotations = maps.Clone(base.Annotations)
+		}
+	}
+
+	return quickStarts, nil
+}
+
+// GetQuickStartConditions returns the conditions of the quickstarts met by the cluster
+func GetQuickStartConditions(ctx context.Context, cli client.Client) (map[string]bool, error) {
+
+	conditions := map[string]bool{}
+
+	storageClusters := &unstructured.UnstructuredList{}
+	storageClusters.SetAPIVersion(storageClusterApiVersion)
+	storageClusters.SetKind(storageClusterKind + "List")
+	if err := cli.List(ctx, storageClusters, client.InNamespace(OperatorNamespace)); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for _, storageCluster := range storageClusters.Items {
+		if external, _, _ := unstructured.NestedBool(storageCluster.Object, "spec", "externalStorage", "enable"); external {
+			conditions[QuickStartConditionExternal] = true
+		} else {
+			conditions[QuickStartConditionInternal] = true
+		}
+	}
+
+	// a StorageClient without a StorageCluster consumes the storage of a provider cluster
+	if len(storageClusters.Items) == 0 {
+		hasStorageClient, err := hasComponentResource(ctx, cli, storageClientApiVersion, storageClientKind)
+		if err != nil {
+			return nil, err
+		}
+		conditions[QuickStartConditionClient] = hasStorageClient
+	}
+
+	hasNooBaa, err := hasComponentResource(ctx, cli, noobaaApiVersion, noobaaKind)
+	if err != nil {
+		return nil, err
+	}
+	conditions[QuickStartConditionNooBaa] = hasNooBaa
+
+	return conditions, nil
+}
+
+// isQuickStartApplicable returns true if the conditions and the OpenShift version range of the quickstart are met
+func isQuickStartApplicable(cqs *consolev1.ConsoleQuickStart, conditions map[string]bool, ocpVersion string) (bool, error) {
+
+	if value := cqs.Annotations[QuickStartConditionsAnnotationKey]; value != "" {
+		for _, condition := range strings.Split(value, ",") {
+			if !conditions[strings.TrimSpace(condition)] {
+				return false, nil
+			}
+		}
+	}
+
+	if value := cqs.Annotations[QuickStartOCPVersionsAnnotationKey]; value != "" {
+		versionRange, err := semver.ParseRange(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid OpenShift versions %q of quickstart %s: %v", value, cqs.Name, err)
+		}
+		version, err := semver.ParseTolerant(ocpVersion)
+		if err != nil {
+			return false, fmt.Errorf("invalid OpenShift version %q: %v", ocpVersion, err)
+		}
+		if !versionRange(version) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ensureQuickStarts create or update the quickstarts which apply to the cluster, and delete
+// the quickstarts which are no longer shipped or no longer apply. The quickstarts are owned by owner, if set.
+func ensureQuickStarts(ctx context.Context, cli client.Client, logger logr.Logger, conditions map[string]bool, ocpVersion string, owner *rbacv1.ClusterRole) error {
+
+	quickStarts, err := loadQuickStarts(quickStartsSubFS())
+	if err != nil {
+		logger.Error(err, "failed to load quickstarts")
+		return err
+	}
+
+	var shippedNames, desiredNames []string
+	for _, desiredCQS := range quickStarts {
+		shippedNames = append(shippedNames, desiredCQS.Name)
+
+		applicable, err := isQuickStartApplicable(desiredCQS, conditions, ocpVersion)
 		if err != nil {
-			logger.Error(err, "failed to unmarshal ConsoleQuickStart", "ConsoleQuickStartString", string(qs))
+			// keep the quickstart as is until it can be checked
+			logger.Error(err, "failed to check quickstart", "Name", desiredCQS.Name)
+			desiredNames = append(desiredNames, desiredCQS.Name)
+			continue
+		}
+		if !applicable {
 			continue
 		}
+		desiredNames = append(desiredNames, desiredCQS.Name)
+
 		cqs := &consolev1.ConsoleQuickStart{}
-		cqs.ObjectMeta = desiredCQS.ObjectMeta
+		cqs.Name = desiredCQS.Name
 		_, err = controllerutil.CreateOrUpdate(ctx, cli, cqs, func() error {
+			if cqs.Labels == nil {
+				cqs.Labels = map[string]string{}
+			}
+			maps.Copy(cqs.Labels, desiredCQS.Labels)
+			cqs.Annotations = desiredCQS.Annotations
 			cqs.Spec = desiredCQS.Spec
-			return nil
+			return setConsoleOwner(owner, cqs, cli.Scheme())
 		})
 		if err != nil {
-			logger.Error(err, "failed to create or update quickstart", "Name", desiredCQS.Name, "Namespace", desiredCQS.Namespace)
+			logger.Error(err, "failed to create or update quickstart", "Name", desiredCQS.Name)
 			return nil
 		}
-		logger.Info("updating quickstarts", "Name", desiredCQS.Name, "Namespace", desiredCQS.Namespace)
+		logger.Info("updating quickstarts", "Name", desiredCQS.Name)
 	}
+
+	return pruneQuickStarts(ctx, cli, logger, shippedNames, desiredNames)
+}
+
+// pruneQuickStarts deletes the quickstarts of odf-operator which are not in keepNames. The quickstarts created
+// by the previous versions are only recognized by name, as they are not labeled.
+func pruneQuickStarts(ctx context.Context, cli client.Client, logger logr.Logger, shippedNames, keepNames []string) error {
+
+	quickStarts := &consolev1.ConsoleQuickStartList{}
+	if err := cli.List(ctx, quickStarts); err != nil {
+		logger.Error(err, "failed to list quickstarts")
+		return err
+	}
+
+	for i := range quickStarts.Items {
+		cqs := &quickStarts.Items[i]
+		if _, managed := cqs.Labels[managedByLabel]; !managed && !slices.Contains(shippedNames, cqs.Name) {
+			continue
+		}
+		if slices.Contains(keepNames, cqs.Name) {
+			continue
+		}
+
+		if err := cli.Delete(ctx, cqs); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "failed to delete quickstart", "Name", cqs.Name)
+			return err
+		}
+		logger.Info("deleted quickstart which no longer applies", "Name", cqs.Name)
+	}
+
 	return nil
 }
 
@@ -55,22 +277,26 @@ func ensureQuickStarts(ctx context.Context, cli client.Client, logger logr.Logge
 // TODO: This function is not used, a call for this function need to be introduced whenever we resolve ODF uninstallation techdebt
 func deleteQuickStarts(ctx context.Context, cli client.Client, logger logr.Logger) { //nolint:unused
 
-	for _, qs := range AllQuickStarts {
-		cqs := consolev1.ConsoleQuickStart{}
-		err := yaml.Unmarshal(qs, &cqs)
-		if err != nil {
-			logger.Error(err, "failed to unmarshal ConsoleQuickStart.", "ConsoleQuickStartString", string(qs))
-			continue
-		}
+	quickStarts, err := loadQuickStarts(quickStartsSubFS())
+	if err != nil {
+		logger.Error(err, "failed to load quickstarts")
+		return
+	}
 
-		err = cli.Delete(ctx, &cqs)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				continue
-			}
-			logger.Error(err, "failed to delete quickstart", "Name", cqs.Name, "Namespace", cqs.Namespace)
-		}
+	var shippedNames []string
+	for _, cqs := range quickStarts {
+		shippedNames = append(shippedNames, cqs.Name)
+	}
+
+	if err := pruneQuickStarts(ctx, cli, logger, shippedNames, nil); err != nil {
+		logger.Error(err, "failed to delete quickstarts")
+	}
+}
 
-		logger.Info("quickstart marked for deletion", "Name", cqs.Name, "Namespace", cqs.Namespace)
+func quickStartsSubFS() fs.FS {
+	fsys, err := fs.Sub(quickStartsFS, "quickstarts")
+	if err != nil {
+		panic(err)
 	}
+	return fsys
 }
@@ -18,19 +18,79 @@ package controllers
 
 import (
 	"testing"
+	"testing/fstest"
 
 	consolev1 "github.com/openshift/api/console/v1"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/yaml"
 )
 
-func TestQuickStartYamls(t *testing.T) {
-	for _, qs := range AllQuickStarts {
-		cqs := consolev1.ConsoleQuickStart{}
-		err := yaml.Unmarshal(qs, &cqs)
-		assert.NoError(t, err)
+func TestLoadQuickStarts(t *testing.T) {
+	quickStarts, err := loadQuickStarts(quickStartsSubFS())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, quickStarts)
+	for _, cqs := range quickStarts {
+		assert.NotEmpty(t, cqs.Spec.DisplayName, cqs.Name)
+		assert.Equal(t, defaultQuickStartLang, cqs.Labels[quickStartLangLabelKey], cqs.Name)
+	}
+
+	fsys := fstest.MapFS{
+		"guide.yaml":    {Data: []byte("metadata:\n  name: guide\n  annotations:\n    " + QuickStartConditionsAnnotationKey + ": noobaa\nspec:\n  displayName: Guide\n")},
+		"guide.ja.yaml": {Data: []byte("metadata:\n  name: guide\nspec:\n  displayName: ガイド\n")},
+	}
+	quickStarts, err = loadQuickStarts(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, quickStarts, 2)
+
+	localized := quickStarts[0]
+	assert.Equal(t, "guide-ja", localized.Name)
+	assert.Equal(t, "ja", localized.Labels[quickStartLangLabelKey])
+	assert.Equal(t, "guide", localized.Labels[quickStartNameLabelKey])
+	assert.Equal(t, "noobaa", localized.Annotations[QuickStartConditionsAnnotationKey])
+
+	// a localized quickstart requires the English one
+	_, err = loadQuickStarts(fstest.MapFS{"guide.ja.yaml": fsys["guide.ja.yaml"]})
+	assert.Error(t, err)
+}
+
+func TestIsQuickStartApplicable(t *testing.T) {
+	cqs := &consolev1.ConsoleQuickStart{}
+	cqs.Annotations = map[string]string{
+		QuickStartConditionsAnnotationKey:  "internal, noobaa",
+		QuickStartOCPVersionsAnnotationKey: ">=4.20.0-0",
+	}
+
+	cases := []struct {
+		label      string
+		conditions map[string]bool
+		ocpVersion string
+		expect     bool
+	}{
+		{
+			label:      "all conditions met",
+			conditions: map[string]bool{QuickStartConditionInternal: true, QuickStartConditionNooBaa: true},
+			ocpVersion: "4.22.0",
+			expect:     true,
+		},
+		{
+			label:      "condition not met",
+			conditions: map[string]bool{QuickStartConditionExternal: true, QuickStartConditionNooBaa: true},
+			ocpVersion: "4.22.0",
+		},
+		{
+			label:      "older OpenShift",
+			conditions: map[string]bool{QuickStartConditionInternal: true, QuickStartConditionNooBaa: true},
+			ocpVersion: "4.19.3",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			applicable, err := isQuickStartApplicable(cqs, c.conditions, c.ocpVersion)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expect, applicable)
+		})
 	}
 }
 
@@ -40,25 +100,50 @@ func TestEnsureQuickStarts(t *testing.T) {
 	cli := testClient
 	logger := testLogger
 
-	err := ensureQuickStarts(ctx, cli, logger)
+	quickStarts, err := loadQuickStarts(quickStartsSubFS())
 	assert.NoError(t, err)
 
-	// Check if all quickstarts are created
-	for _, qs := range AllQuickStarts {
-		expected := &consolev1.ConsoleQuickStart{}
-		err := yaml.Unmarshal(qs, expected)
+	// a quickstart of another operator
+	other := &consolev1.ConsoleQuickStart{}
+	other.Name = "other-quickstart"
+	assert.NoError(t, cli.Create(ctx, other))
+
+	// a quickstart of a previous version, which is not labeled
+	previous := &consolev1.ConsoleQuickStart{}
+	previous.Name = "odf-configuration"
+	assert.NoError(t, cli.Create(ctx, previous))
+
+	err = ensureQuickStarts(ctx, cli, logger, map[string]bool{QuickStartConditionExternal: true}, "4.22.0", nil)
+	assert.NoError(t, err)
+
+	for _, expected := range quickStarts {
+		found := &consolev1.ConsoleQuickStart{}
+		err = cli.Get(ctx, client.ObjectKeyFromObject(expected), found)
+		if expected.Annotations[QuickStartConditionsAnnotationKey] == QuickStartConditionInternal {
+			assert.True(t, errors.IsNotFound(err), expected.Name)
+			continue
+		}
 		assert.NoError(t, err)
+		assert.Equal(t, expected.Spec.DisplayName, found.Spec.DisplayName)
+		assert.Contains(t, found.Labels, managedByLabel)
+	}
 
+	// Check if all quickstarts are created in internal mode
+	err = ensureQuickStarts(ctx, cli, logger, map[string]bool{QuickStartConditionInternal: true}, "4.22.0", nil)
+	assert.NoError(t, err)
+
+	for _, expected := range quickStarts {
 		found := &consolev1.ConsoleQuickStart{}
 		err = cli.Get(ctx, client.ObjectKeyFromObject(expected), found)
 		assert.NoError(t, err)
 
 		assert.Equal(t, expected.Name, found.Name)
-		assert.Equal(t, expected.Namespace, found.Namespace)
 		assert.Equal(t, expected.Spec.DurationMinutes, found.Spec.DurationMinutes)
 		assert.Equal(t, expected.Spec.Introduction, found.Spec.Introduction)
 		assert.Equal(t, expected.Spec.DisplayName, found.Spec.DisplayName)
 	}
+
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(other), other))
 }
 
 func TestDeleteQuickStarts(t *testing.T) {
@@ -69,12 +154,11 @@ func TestDeleteQuickStarts(t *testing.T) {
 
 	deleteQuickStarts(ctx, cli, logger)
 
-	// Check if all quickstarts are deleted
-	for _, qs := range AllQuickStarts {
-		expected := &consolev1.ConsoleQuickStart{}
-		err := yaml.Unmarshal(qs, expected)
-		assert.NoError(t, err)
+	quickStarts, err := loadQuickStarts(quickStartsSubFS())
+	assert.NoError(t, err)
 
+	// Check if all quickstarts are deleted
+	for _, expected := range quickStarts {
 		found := &consolev1.ConsoleQuickStart{}
 		err = cli.Get(ctx, client.ObjectKeyFromObject(expected), found)
 		assert.Error(t, err)
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+const (
+	// SubscriptionCustomizationDocURL documents the supported ways to customize the managed subscriptions
+	SubscriptionCustomizationDocURL = "https://github.com/red-hat-storage/odf-operator/blob/main/docs/customizing-subscriptions.md"
+)
+
+// RejectConflictingSubscriptionEdits returns true if the subscription webhook has to reject
+// the edits conflicting with the desired state instead of only warning about them.
+func RejectConflictingSubscriptionEdits() bool {
+
+	reject, err := strconv.ParseBool(os.Getenv("REJECT_CONFLICTING_SUBSCRIPTION_EDITS"))
+
+	return err == nil && reject
+}
+
+// GetSubscriptionConflicts returns the changes made to the subscription of the package in the record
+// which conflict with the state desired by odf-operator. Only the fields changed from oldSub are checked,
+// oldSub is nil on creation.
+func GetSubscriptionConflicts(sub, oldSub *opv1a1.Subscription, record *OdfOperatorConfigMapRecord, odfSub *opv1a1.Subscription) []string {
+
+	var conflicts []string
+
+	if sub.Spec == nil {
+		return conflicts
+	}
+
+	oldSpec := &opv1a1.SubscriptionSpec{}
+	if oldSub != nil && oldSub.Spec != nil {
+		oldSpec = oldSub.Spec
+	}
+	isChanged := func(field func(*opv1a1.SubscriptionSpec) any) bool {
+		return oldSub == nil || !equality.Semantic.DeepEqual(field(sub.Spec), field(oldSpec))
+	}
+
+	if record.Channel != "" && sub.Spec.Channel != record.Channel &&
+		isChanged(func(s *opv1a1.SubscriptionSpec) any { return s.Channel }) {
+		conflicts = append(conflicts, fmt.Sprintf("channel %q differs from the channel %q of ODF", sub.Spec.Channel, record.Channel))
+	}
+
+	if odfSub == nil || odfSub.Spec == nil {
+		return conflicts
+	}
+
+	// the dependencies have to come from the same catalog as odf-operator, a different catalog mixes versions
+	if slices.Contains(DepsSubscriptionPackageNames, sub.Spec.Package) &&
+		(sub.Spec.CatalogSource != odfSub.Spec.CatalogSource || sub.Spec.CatalogSourceNamespace != odfSub.Spec.CatalogSourceNamespace) &&
+		isChanged(func(s *opv1a1.SubscriptionSpec) any { return []string{s.CatalogSource, s.CatalogSourceNamespace} }) {
+		conflicts = append(conflicts, fmt.Sprintf("catalog %s/%s differs from the catalog %s/%s of odf-operator",
+			sub.Spec.CatalogSourceNamespace, sub.Spec.CatalogSource, odfSub.Spec.CatalogSourceNamespace, odfSub.Spec.CatalogSource))
+	}
+
+	config := sub.Spec.Config
+	if config == nil {
+		config = &opv1a1.SubscriptionConfig{}
+	}
+
+	if odfSub.Spec.Config != nil && isChanged(func(s *opv1a1.SubscriptionSpec) any { return getSubscriptionTolerations(s) }) {
+		for _, toleration := range odfSub.Spec.Config.Tolerations {
+			if !slices.ContainsFunc(config.Tolerations, func(t corev1.Toleration) bool { return t.MatchToleration(&toleration) }) {
+				conflicts = append(conflicts, fmt.Sprintf("toleration %q of odf-operator is removed", toleration.Key))
+			}
+		}
+	}
+
+	desiredSub := &opv1a1.Subscription{Spec: &opv1a1.SubscriptionSpec{Package: sub.Spec.Package}}
+	AdjustSpecialCasesSubscriptionConfig(desiredSub)
+	if desiredSub.Spec.Config != nil && isChanged(func(s *opv1a1.SubscriptionSpec) any { return getSubscriptionEnv(s) }) {
+		for _, desiredEnv := range desiredSub.Spec.Config.Env {
+			if !slices.Contains(config.Env, desiredEnv) {
+				conflicts = append(conflicts, fmt.Sprintf("env %q differs from the value set by odf-operator", desiredEnv.Name))
+			}
+		}
+	}
+
+	return conflicts
+}
+
+func getSubscriptionTolerations(spec *opv1a1.SubscriptionSpec) []corev1.Toleration {
+
+	if spec.Config == nil {
+		return nil
+	}
+
+	return spec.Config.Tolerations
+}
+
+func getSubscriptionEnv(spec *opv1a1.SubscriptionSpec) []corev1.EnvVar {
+
+	if spec.Config == nil {
+		return nil
+	}
+
+	return spec.Config.Env
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetSubscriptionConflicts(t *testing.T) {
+	toleration := corev1.Toleration{Key: "node.ocs.openshift.io/storage", Operator: "Equal", Value: "true", Effect: "NoSchedule"}
+
+	odfSub := &opv1a1.Subscription{Spec: &opv1a1.SubscriptionSpec{
+		Package:                OdfSubscriptionPackage,
+		CatalogSource:          "redhat-operators",
+		CatalogSourceNamespace: "openshift-marketplace",
+		Config:                 &opv1a1.SubscriptionConfig{Tolerations: []corev1.Toleration{toleration}},
+	}}
+	record := &OdfOperatorConfigMapRecord{Pkg: OdfDepsSubscriptionPackage, Channel: "stable-4.22"}
+
+	newSub := func(channel, catalog string, tolerations ...corev1.Toleration) *opv1a1.Subscription {
+		return &opv1a1.Subscription{Spec: &opv1a1.SubscriptionSpec{
+			Package:                OdfDepsSubscriptionPackage,
+			Channel:                channel,
+			CatalogSource:          catalog,
+			CatalogSourceNamespace: "openshift-marketplace",
+			Config:                 &opv1a1.SubscriptionConfig{Tolerations: tolerations},
+		}}
+	}
+	desired := newSub("stable-4.22", "redhat-operators", toleration)
+
+	cases := []struct {
+		label           string
+		sub             *opv1a1.Subscription
+		oldSub          *opv1a1.Subscription
+		expectConflicts int
+	}{
+		{
+			label:  "desired state",
+			sub:    desired,
+			oldSub: desired,
+		},
+		{
+			label:           "channel changed",
+			sub:             newSub("stable-4.21", "redhat-operators", toleration),
+			oldSub:          desired,
+			expectConflicts: 1,
+		},
+		{
+			label:           "catalog changed",
+			sub:             newSub("stable-4.22", "custom-catalog", toleration),
+			oldSub:          desired,
+			expectConflicts: 1,
+		},
+		{
+			label:           "toleration removed",
+			sub:             newSub("stable-4.22", "redhat-operators"),
+			oldSub:          desired,
+			expectConflicts: 1,
+		},
+		{
+			label:  "unrelated update of a subscription already drifted",
+			sub:    newSub("stable-4.21", "custom-catalog"),
+			oldSub: newSub("stable-4.21", "custom-catalog"),
+		},
+		{
+			label:           "created with a different version",
+			sub:             newSub("stable-4.21", "custom-catalog"),
+			expectConflicts: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			conflicts := GetSubscriptionConflicts(c.sub, c.oldSub, record, odfSub)
+			assert.Len(t, conflicts, c.expectConflicts, conflicts)
+		})
+	}
+
+	// only the channel is checked without the odf-operator subscription
+	conflicts := GetSubscriptionConflicts(newSub("stable-4.21", "custom-catalog"), nil, record, nil)
+	assert.Len(t, conflicts, 1)
+}
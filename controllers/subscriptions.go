@@ -26,6 +26,7 @@ import (
 	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -241,6 +242,35 @@ func EnsureDesiredSubscription(ctx context.Context, cli client.Client, olmPkgRec
 	return nil
 }
 
+// ApplyDesiredSubscriptionConfig applies the catalog, tolerations and env of the desired subscription
+// to the subscription being created and returns true if it was changed. The channel is left as is,
+// it is chosen by whoever creates the subscription and reconciled by EnsureDesiredSubscription.
+func ApplyDesiredSubscriptionConfig(sub, desiredSubscription *opv1a1.Subscription) bool {
+
+	if sub.Spec == nil || desiredSubscription.Spec == nil {
+		return false
+	}
+
+	original := sub.Spec.DeepCopy()
+
+	if desiredSubscription.Spec.CatalogSource != "" {
+		sub.Spec.CatalogSource = desiredSubscription.Spec.CatalogSource
+		sub.Spec.CatalogSourceNamespace = desiredSubscription.Spec.CatalogSourceNamespace
+	}
+
+	if desiredSubscription.Spec.Config != nil {
+		if sub.Spec.Config == nil {
+			sub.Spec.Config = &opv1a1.SubscriptionConfig{}
+		}
+		sub.Spec.Config.Tolerations = getMergedTolerations(sub.Spec.Config.Tolerations, desiredSubscription.Spec.Config.Tolerations)
+		if len(desiredSubscription.Spec.Config.Env) > 0 {
+			sub.Spec.Config.Env = getMergedEnvVars(sub.Spec.Config.Env, desiredSubscription.Spec.Config.Env)
+		}
+	}
+
+	return !equality.Semantic.DeepEqual(original, sub.Spec)
+}
+
 func SetOdfSubControllerReference(ctx context.Context, cli client.Client, obj client.Object) error {
 
 	odfSub, err := GetOdfSubscription(ctx, cli)
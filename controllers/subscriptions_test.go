@@ -272,3 +272,66 @@ func TestEnsureDesiredSubscription_CacheRaceWipesResources(t *testing.T) {
 		t.Errorf("CPU request = %s, want %s", gotCPUReq.String(), wantCPUReq.String())
 	}
 }
+
+// TestApplyDesiredSubscriptionConfig verifies that a subscription created by
+// OLM dependency resolution gets the tolerations and env desired by ODF while
+// keeping its own channel and catalog.
+func TestApplyDesiredSubscriptionConfig(t *testing.T) {
+	t.Setenv("ROLEARN", "arn:aws:iam::123:role/noobaa")
+
+	const operatorNs = "openshift-storage"
+
+	cli := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(newOdfSubscription(operatorNs)).
+		Build()
+
+	record := &OlmPkgRecord{
+		Channel:   "stable-4.19",
+		Csv:       "mcg-operator.v4.19.0",
+		Pkg:       "mcg-operator",
+		Namespace: operatorNs,
+	}
+
+	desiredSubscription, err := GetDesiredSubscription(context.Background(), cli, record)
+	if err != nil {
+		t.Fatalf("GetDesiredSubscription() error: %v", err)
+	}
+
+	sub := &opv1a1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mcg-operator-stable-4.19-redhat-operators-openshift-marketplace",
+			Namespace: operatorNs,
+		},
+		Spec: &opv1a1.SubscriptionSpec{
+			Package:                "mcg-operator",
+			Channel:                "stable-4.19",
+			CatalogSource:          "redhat-operators",
+			CatalogSourceNamespace: "openshift-marketplace",
+			Config: &opv1a1.SubscriptionConfig{
+				Env: []corev1.EnvVar{{Name: "USER_SET", Value: "1"}},
+			},
+		},
+	}
+
+	if !ApplyDesiredSubscriptionConfig(sub, desiredSubscription) {
+		t.Fatal("ApplyDesiredSubscriptionConfig() = false, want true")
+	}
+
+	if sub.Spec.CatalogSource != "redhat-operators" || sub.Spec.Channel != "stable-4.19" {
+		t.Errorf("catalog or channel changed: %s %s", sub.Spec.CatalogSource, sub.Spec.Channel)
+	}
+
+	if len(sub.Spec.Config.Tolerations) != 1 || sub.Spec.Config.Tolerations[0].Key != "node.ocs.openshift.io/storage" {
+		t.Errorf("Tolerations = %v, want the odf-operator tolerations", sub.Spec.Config.Tolerations)
+	}
+
+	wantEnv := []corev1.EnvVar{{Name: "ROLEARN", Value: "arn:aws:iam::123:role/noobaa"}, {Name: "USER_SET", Value: "1"}}
+	if len(sub.Spec.Config.Env) != len(wantEnv) || sub.Spec.Config.Env[0] != wantEnv[0] || sub.Spec.Config.Env[1] != wantEnv[1] {
+		t.Errorf("Env = %v, want %v", sub.Spec.Config.Env, wantEnv)
+	}
+
+	if ApplyDesiredSubscriptionConfig(sub, desiredSubscription) {
+		t.Error("ApplyDesiredSubscriptionConfig() = true on an already configured subscription, want false")
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	configv1 "github.com/openshift/api/config/v1"
+	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TLSProfileDomain is the domain of the TLSProfile selectors matching the servers of ODF
+	TLSProfileDomain = "odf.openshift.io"
+
+	// The server names of the TLSProfile selectors, e.g. "odf.openshift.io/webhook"
+	TLSServerConsole        = "console"
+	TLSServerWebhook        = "webhook"
+	TLSServerMetrics        = "metrics"
+	TLSServerUXBackend      = "ux-backend"
+	TLSServerUXBackendProxy = "ux-backend-proxy"
+)
+
+// operatorTLSServers are the servers run by the operator process itself
+var operatorTLSServers = []string{TLSServerWebhook, TLSServerMetrics}
+
+// TLSSource resolves the TLS config of the servers. A matching rule of the TLSProfile overrides the
+// TLS security profile of the cluster APIServer.
+type TLSSource struct {
+	TLSProfile *ocstlsv1.TLSProfile
+	APIServer  *ocstlsv1.TLSConfig
+}
+
+// GetTLSSource returns the TLS source of the servers. The TLSProfile is only read when withTLSProfile
+// is set, as its CRD may not be installed.
+func GetTLSSource(ctx context.Context, cli client.Client, logger logr.Logger, withTLSProfile bool) (*TLSSource, error) {
+
+	source := &TLSSource{}
+
+	if withTLSProfile {
+		tlsProfile, err := GetTLSProfile(ctx, cli)
+		if err != nil {
+			return nil, err
+		}
+		source.TLSProfile = tlsProfile
+	}
+
+	apiServer := &configv1.APIServer{}
+	apiServer.Name = "cluster"
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(apiServer), apiServer); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	} else if err == nil {
+		source.APIServer = GetAPIServerTLSConfig(logger, apiServer.Spec.TLSSecurityProfile)
+	}
+
+	return source, nil
+}
+
+// GetTLSProfile returns the ocs TLSProfile, or nil if neither the TLSProfile nor its CRD exist.
+func GetTLSProfile(ctx context.Context, cli client.Client) (*ocstlsv1.TLSProfile, error) {
+
+	tlsProfile := &ocstlsv1.TLSProfile{}
+	tlsProfile.Name = TLSProfileName
+	tlsProfile.Namespace = OperatorNamespace
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(tlsProfile), tlsProfile); err != nil {
+		if client.IgnoreNotFound(err) == nil || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return tlsProfile, nil
+}
+
+// GetAPIServerTLSConfig converts the TLS security profile of the cluster APIServer to a TLSProfile config.
+// It returns nil, the server defaults, unless the profile can be expressed exactly by a TLSProfile config.
+//
+// The TLSProfile versions are exact, so only the profiles with a TLS 1.3 minimum are converted. A lower
+// minimum would pin TLS 1.2 and disable TLS 1.3 and its groups, which the server defaults keep. The
+// TLS 1.2 ciphers of a TLS 1.3 profile are never negotiated and are ignored.
+func GetAPIServerTLSConfig(logger logr.Logger, profile *configv1.TLSSecurityProfile) *ocstlsv1.TLSConfig {
+
+	if profile == nil {
+		return nil
+	}
+
+	var spec *configv1.TLSProfileSpec
+	switch profile.Type {
+	case configv1.TLSProfileModernType:
+		spec = configv1.TLSProfiles[configv1.TLSProfileModernType]
+	case configv1.TLSProfileCustomType:
+		if profile.Custom != nil {
+			spec = &profile.Custom.TLSProfileSpec
+		}
+	}
+	if spec == nil || spec.MinTLSVersion != configv1.VersionTLS13 {
+		return nil
+	}
+
+	cfg := &ocstlsv1.TLSConfig{Version: ocstlsv1.VersionTLS1_3}
+	for _, cipher := range spec.Ciphers {
+		ianaCipher := ocstlsv1.TLSCipherSuite(cipher)
+		if ocstlsv1.ValidateTLSConfig(&ocstlsv1.TLSConfig{Version: cfg.Version, Ciphers: []ocstlsv1.TLSCipherSuite{ianaCipher}}) == nil {
+			cfg.Ciphers = append(cfg.Ciphers, ianaCipher)
+			continue
+		}
+		// the TLS 1.3 ciphers are the only ones named TLS_ in the OpenSSL names of the profiles
+		if strings.HasPrefix(cipher, "TLS_") {
+			logger.Info("APIServer TLS security profile has a TLS 1.3 cipher not supported by the TLSProfile, using the server defaults", "cipher", cipher)
+			return nil
+		}
+	}
+
+	if len(cfg.Ciphers) == 0 {
+		logger.Info("APIServer TLS security profile has no TLS 1.3 cipher, using the server defaults", "type", profile.Type)
+		return nil
+	}
+
+	return cfg
+}
+
+// GetServerTLSConfig returns the TLS config of the server, or nil if the server has to keep its defaults.
+func (s *TLSSource) GetServerTLSConfig(logger logr.Logger, server string) *ocstlsv1.TLSConfig {
+
+	if s == nil {
+		return nil
+	}
+
+	cfg, found := ocstlsv1.GetConfigForServer(s.TLSProfile, TLSProfileDomain, server)
+	if !found {
+		return s.APIServer
+	}
+
+	if err := ocstlsv1.ValidateTLSConfig(cfg); err != nil {
+		logger.Error(err, "Invalid TLSProfile config, using the APIServer TLS security profile or the server defaults", "server", server)
+		return s.APIServer
+	}
+
+	return cfg
+}
+
+// GetTLSConfigEnv returns the env passing the TLS config to the servers running outside of the operator.
+// The values are empty when the servers keep their defaults.
+func GetTLSConfigEnv(cfg *ocstlsv1.TLSConfig) []corev1.EnvVar {
+
+	var version string
+	var ciphers, groups []string
+	if cfg != nil {
+		version = string(cfg.Version)
+		for _, cipher := range cfg.Ciphers {
+			ciphers = append(ciphers, string(cipher))
+		}
+		for _, group := range cfg.Groups {
+			groups = append(groups, string(group))
+		}
+	}
+
+	return []corev1.EnvVar{
+		{Name: "TLS_PROFILE_VERSION", Value: version},
+		{Name: "TLS_PROFILE_CIPHERS", Value: strings.Join(ciphers, ",")},
+		{Name: "TLS_PROFILE_GROUPS", Value: strings.Join(groups, ",")},
+	}
+}
+
+// GetOAuthProxyTLSArgs returns the oauth-proxy args applying the TLS config. oauth-proxy only supports
+// a minimum version and the TLS 1.2 ciphers, the groups are left to its defaults.
+func GetOAuthProxyTLSArgs(cfg *ocstlsv1.TLSConfig) []string {
+
+	if cfg == nil {
+		return nil
+	}
+
+	var args []string
+	switch cfg.Version {
+	case ocstlsv1.VersionTLS1_2:
+		args = append(args, "-tls-min-version=VersionTLS12")
+		for _, cipher := range cfg.Ciphers {
+			args = append(args, "-tls-cipher-suite="+string(cipher))
+		}
+	case ocstlsv1.VersionTLS1_3:
+		args = append(args, "-tls-min-version=VersionTLS13")
+	}
+
+	return args
+}
+
+// ServerTLSConfigs holds the TLS configs of the servers run by the operator. The servers read it on every
+// handshake, so an update of the TLS source is applied without restarting them.
+type ServerTLSConfigs struct {
+	mu      sync.RWMutex
+	configs map[string]*ocstlsv1.TLSConfig
+}
+
+func NewServerTLSConfigs() *ServerTLSConfigs {
+	return &ServerTLSConfigs{configs: map[string]*ocstlsv1.TLSConfig{}}
+}
+
+// Update sets the TLS configs of the operator servers from the TLS source. It returns true if any config changed.
+func (s *ServerTLSConfigs) Update(logger logr.Logger, source *TLSSource) bool {
+
+	configs := map[string]*ocstlsv1.TLSConfig{}
+	for _, server := range operatorTLSServers {
+		if cfg := source.GetServerTLSConfig(logger, server); cfg != nil {
+			configs[server] = cfg.DeepCopy()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if equality.Semantic.DeepEqual(s.configs, configs) {
+		return false
+	}
+	s.configs = configs
+	logger.Info("TLS config of the operator servers updated")
+
+	return true
+}
+
+// Get returns the crypto/tls config of the server, or nil if the server keeps the Go defaults.
+func (s *ServerTLSConfigs) Get(server string) *tls.Config {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cfg, ok := s.configs[server]
+	if !ok {
+		return nil
+	}
+
+	return ocstlsv1.GetGoTLSConfig(cfg)
+}
+
+// TLSOpt returns the option to pass in the TLSOpts of a controller-runtime server. It resolves the config of
+// the server on every handshake, on top of the config built by controller-runtime (certificate, protocols).
+func (s *ServerTLSConfigs) TLSOpt(server string) func(*tls.Config) {
+	return func(base *tls.Config) {
+		base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := s.Get(server)
+			if cfg == nil {
+				// keep using the base config
+				return nil, nil
+			}
+
+			serverCfg := base.Clone()
+			serverCfg.GetConfigForClient = nil
+			serverCfg.MinVersion = cfg.MinVersion
+			serverCfg.MaxVersion = cfg.MaxVersion
+			serverCfg.CipherSuites = cfg.CipherSuites
+			serverCfg.CurvePreferences = cfg.CurvePreferences
+
+			return serverCfg, nil
+		}
+	}
+}
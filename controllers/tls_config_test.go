@@ -0,0 +1,247 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
+	"github.com/stretchr/testify/assert"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestTLSProfile() *ocstlsv1.TLSProfile {
+	tlsProfile := &ocstlsv1.TLSProfile{}
+	tlsProfile.Name = TLSProfileName
+	tlsProfile.Namespace = OperatorNamespace
+	tlsProfile.Spec.Rules = []ocstlsv1.TLSProfileRules{
+		{
+			Selectors: []ocstlsv1.Selector{"*"},
+			Config: ocstlsv1.TLSConfig{
+				Version: ocstlsv1.VersionTLS1_2,
+				Ciphers: []ocstlsv1.TLSCipherSuite{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				Groups:  []ocstlsv1.TLSGroupName{"secp256r1"},
+			},
+		},
+		{
+			Selectors: []ocstlsv1.Selector{"odf.openshift.io/webhook"},
+			Config: ocstlsv1.TLSConfig{
+				Version: ocstlsv1.VersionTLS1_3,
+				Ciphers: []ocstlsv1.TLSCipherSuite{"TLS_AES_256_GCM_SHA384"},
+				Groups:  []ocstlsv1.TLSGroupName{"X25519MLKEM768"},
+			},
+		},
+		{
+			// hybrid groups are not valid for TLS 1.2
+			Selectors: []ocstlsv1.Selector{"odf.openshift.io/metrics"},
+			Config: ocstlsv1.TLSConfig{
+				Version: ocstlsv1.VersionTLS1_2,
+				Ciphers: []ocstlsv1.TLSCipherSuite{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				Groups:  []ocstlsv1.TLSGroupName{"X25519MLKEM768"},
+			},
+		},
+	}
+	return tlsProfile
+}
+
+func TestGetServerTLSConfig(t *testing.T) {
+	source := &TLSSource{TLSProfile: newTestTLSProfile()}
+
+	cfg := source.GetServerTLSConfig(testLogger, TLSServerWebhook)
+	assert.NotNil(t, cfg)
+	assert.Equal(t, ocstlsv1.VersionTLS1_3, cfg.Version)
+
+	cfg = source.GetServerTLSConfig(testLogger, TLSServerUXBackend)
+	assert.NotNil(t, cfg)
+	assert.Equal(t, ocstlsv1.VersionTLS1_2, cfg.Version)
+
+	// an invalid config does not fall back to a less specific rule
+	assert.Nil(t, source.GetServerTLSConfig(testLogger, TLSServerMetrics))
+
+	// the APIServer profile is used for the servers without a valid rule
+	source.APIServer = &ocstlsv1.TLSConfig{Version: ocstlsv1.VersionTLS1_3}
+	assert.Equal(t, source.APIServer, source.GetServerTLSConfig(testLogger, TLSServerMetrics))
+	assert.Equal(t, ocstlsv1.VersionTLS1_2, source.GetServerTLSConfig(testLogger, TLSServerUXBackend).Version)
+
+	source.TLSProfile = nil
+	assert.Equal(t, source.APIServer, source.GetServerTLSConfig(testLogger, TLSServerWebhook))
+
+	assert.Nil(t, (&TLSSource{}).GetServerTLSConfig(testLogger, TLSServerWebhook))
+	assert.Nil(t, (*TLSSource)(nil).GetServerTLSConfig(testLogger, TLSServerWebhook))
+}
+
+func TestGetAPIServerTLSConfig(t *testing.T) {
+	cases := []struct {
+		label   string
+		profile *configv1.TLSSecurityProfile
+		expect  *ocstlsv1.TLSConfig
+	}{
+		{
+			label: "not set",
+		},
+		{
+			label:   "old",
+			profile: &configv1.TLSSecurityProfile{Type: configv1.TLSProfileOldType},
+		},
+		{
+			label:   "intermediate",
+			profile: &configv1.TLSSecurityProfile{Type: configv1.TLSProfileIntermediateType},
+		},
+		{
+			label:   "unknown type",
+			profile: &configv1.TLSSecurityProfile{},
+		},
+		{
+			label:   "modern",
+			profile: &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType},
+			expect: &ocstlsv1.TLSConfig{
+				Version: ocstlsv1.VersionTLS1_3,
+				Ciphers: []ocstlsv1.TLSCipherSuite{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384", "TLS_CHACHA20_POLY1305_SHA256"},
+			},
+		},
+		{
+			label: "custom TLS 1.3",
+			profile: &configv1.TLSSecurityProfile{
+				Type: configv1.TLSProfileCustomType,
+				Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+					Ciphers:       []string{"TLS_AES_256_GCM_SHA384", "ECDHE-RSA-AES256-GCM-SHA384"},
+					MinTLSVersion: configv1.VersionTLS13,
+				}},
+			},
+			expect: &ocstlsv1.TLSConfig{
+				Version: ocstlsv1.VersionTLS1_3,
+				Ciphers: []ocstlsv1.TLSCipherSuite{"TLS_AES_256_GCM_SHA384"},
+			},
+		},
+		{
+			label: "custom TLS 1.3 with an unsupported TLS 1.3 cipher",
+			profile: &configv1.TLSSecurityProfile{
+				Type: configv1.TLSProfileCustomType,
+				Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+					Ciphers:       []string{"TLS_AES_256_GCM_SHA384", "TLS_AES_128_CCM_SHA256"},
+					MinTLSVersion: configv1.VersionTLS13,
+				}},
+			},
+		},
+		{
+			label: "custom TLS 1.3 without TLS 1.3 cipher",
+			profile: &configv1.TLSSecurityProfile{
+				Type: configv1.TLSProfileCustomType,
+				Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+					Ciphers:       []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+					MinTLSVersion: configv1.VersionTLS13,
+				}},
+			},
+		},
+		{
+			label: "custom TLS 1.2",
+			profile: &configv1.TLSSecurityProfile{
+				Type: configv1.TLSProfileCustomType,
+				Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+					Ciphers:       []string{"TLS_AES_128_GCM_SHA256", "ECDHE-RSA-AES256-GCM-SHA384"},
+					MinTLSVersion: configv1.VersionTLS12,
+				}},
+			},
+		},
+		{
+			label:   "custom without spec",
+			profile: &configv1.TLSSecurityProfile{Type: configv1.TLSProfileCustomType},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			assert.Equal(t, c.expect, GetAPIServerTLSConfig(testLogger, c.profile))
+		})
+	}
+}
+
+func TestGetTLSSource(t *testing.T) {
+	scheme := newTestScheme()
+	utilruntime.Must(configv1.AddToScheme(scheme))
+	utilruntime.Must(ocstlsv1.AddToScheme(scheme))
+
+	apiServer := &configv1.APIServer{}
+	apiServer.Name = "cluster"
+	apiServer.Spec.TLSSecurityProfile = &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(apiServer, newTestTLSProfile()).Build()
+
+	source, err := GetTLSSource(context.Background(), cli, testLogger, false)
+	assert.NoError(t, err)
+	assert.Nil(t, source.TLSProfile)
+	assert.Equal(t, ocstlsv1.VersionTLS1_3, source.APIServer.Version)
+
+	source, err = GetTLSSource(context.Background(), cli, testLogger, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, source.TLSProfile)
+
+	cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+	source, err = GetTLSSource(context.Background(), cli, testLogger, true)
+	assert.NoError(t, err)
+	assert.Equal(t, &TLSSource{}, source)
+}
+
+func TestServerTLSConfigs(t *testing.T) {
+	serverTLSConfigs := NewServerTLSConfigs()
+
+	base := &tls.Config{NextProtos: []string{"h2"}}
+	serverTLSConfigs.TLSOpt(TLSServerWebhook)(base)
+
+	// no TLSProfile, the base config is used
+	cfg, err := base.GetConfigForClient(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	assert.True(t, serverTLSConfigs.Update(testLogger, &TLSSource{TLSProfile: newTestTLSProfile()}))
+	assert.False(t, serverTLSConfigs.Update(testLogger, &TLSSource{TLSProfile: newTestTLSProfile()}))
+
+	cfg, err = base.GetConfigForClient(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.MaxVersion)
+	assert.Equal(t, []tls.CurveID{tls.X25519MLKEM768}, cfg.CurvePreferences)
+	assert.Equal(t, []string{"h2"}, cfg.NextProtos)
+	assert.Nil(t, cfg.GetConfigForClient)
+
+	// the metrics config is invalid, the server keeps its defaults
+	assert.Nil(t, serverTLSConfigs.Get(TLSServerMetrics))
+
+	// the TLSProfile is deleted
+	assert.True(t, serverTLSConfigs.Update(testLogger, &TLSSource{}))
+	cfg, err = base.GetConfigForClient(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestGetOAuthProxyTLSArgs(t *testing.T) {
+	source := &TLSSource{TLSProfile: newTestTLSProfile()}
+
+	assert.Equal(t, []string{
+		"-tls-min-version=VersionTLS12",
+		"-tls-cipher-suite=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	}, GetOAuthProxyTLSArgs(source.GetServerTLSConfig(testLogger, TLSServerUXBackendProxy)))
+
+	assert.Equal(t, []string{"-tls-min-version=VersionTLS13"},
+		GetOAuthProxyTLSArgs(source.GetServerTLSConfig(testLogger, TLSServerWebhook)))
+
+	assert.Nil(t, GetOAuthProxyTLSArgs(nil))
+}
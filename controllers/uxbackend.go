@@ -5,11 +5,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"slices"
 
+	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -28,7 +30,7 @@ func generateSessionSecret() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-func getUXBackendServerDeployment(tolerations []corev1.Toleration) *appsv1.Deployment {
+func getUXBackendServerDeployment(config UXBackendConfig, sessionSecretKey string, serverTLSConfig, proxyTLSConfig *ocstlsv1.TLSConfig) *appsv1.Deployment {
 
 	labels := map[string]string{
 		"app.kubernetes.io/component": "ux-backend-server",
@@ -51,14 +53,21 @@ func getUXBackendServerDeployment(tolerations []corev1.Toleration) *appsv1.Deplo
 		Value:    "true",
 	}
 
-	tolerations = append(tolerations, storageToleration)
+	tolerations := append(slices.Clone(config.Tolerations), storageToleration)
 
 	deploymentSpec := &appsv1.DeploymentSpec{
-		Replicas: ptr.To(int32(1)),
+		Replicas: ptr.To(config.Replicas),
 		Selector: &metav1.LabelSelector{
 			MatchLabels: labels,
 		},
-		Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+		// a new pod is ready before an old one is removed, so the console keeps its backend during the rollout
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       ptr.To(intstr.FromInt32(1)),
+				MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+			},
+		},
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: labels,
@@ -114,16 +123,7 @@ func getUXBackendServerDeployment(tolerations []corev1.Toleration) *appsv1.Deplo
 								},
 							},
 						},
-						Resources: corev1.ResourceRequirements{
-							Requests: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("10m"),
-								corev1.ResourceMemory: resource.MustParse("128Mi"),
-							},
-							Limits: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("250m"),
-								corev1.ResourceMemory: resource.MustParse("512Mi"),
-							},
-						},
+						Resources: config.ServerResources,
 						SecurityContext: &corev1.SecurityContext{
 							RunAsNonRoot:           ptr.To(true),
 							ReadOnlyRootFilesystem: ptr.To(true),
@@ -149,25 +149,18 @@ func getUXBackendServerDeployment(tolerations []corev1.Toleration) *appsv1.Deplo
 							"-upstream=http://localhost:8080/",
 							"-tls-cert=/etc/tls/private/tls.crt",
 							"-tls-key=/etc/tls/private/tls.key",
-							"-cookie-secret-file=/etc/proxy/secrets/session_secret",
+							"-cookie-secret-file=/etc/proxy/secrets/" + sessionSecretKey,
 							"-openshift-service-account=ux-backend-server",
-							`-openshift-delegate-urls={"/":{"group":"ocs.openshift.io","resource":"storageclusters","namespace":"openshift-storage","verb":"create"},"/info/":{"group":"authorization.k8s.io","resource":"selfsubjectaccessreviews","verb":"create"}}`,
+							"-openshift-delegate-urls=" + getOAuthProxyDelegateURLs(OperatorNamespace),
+							// the access token of the user is passed to ux-backend, which authorizes each request with it
+							"-pass-access-token=true",
 							"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"},
 						Ports: []corev1.ContainerPort{
 							{
 								ContainerPort: 8888,
 							},
 						},
-						Resources: corev1.ResourceRequirements{
-							Requests: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("5m"),
-								corev1.ResourceMemory: resource.MustParse("50Mi"),
-							},
-							Limits: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("25m"),
-								corev1.ResourceMemory: resource.MustParse("256Mi"),
-							},
-						},
+						Resources: config.ProxyResources,
 						SecurityContext: &corev1.SecurityContext{
 							RunAsNonRoot:           ptr.To(true),
 							ReadOnlyRootFilesystem: ptr.To(true),
@@ -201,6 +194,8 @@ func getUXBackendServerDeployment(tolerations []corev1.Toleration) *appsv1.Deplo
 						},
 					},
 				},
+				NodeSelector:       config.NodeSelector,
+				Affinity:           config.Affinity,
 				Tolerations:        tolerations,
 				PriorityClassName:  "system-cluster-critical",
 				ServiceAccountName: "ux-backend-server",
@@ -208,10 +203,57 @@ func getUXBackendServerDeployment(tolerations []corev1.Toleration) *appsv1.Deplo
 		},
 	}
 
+	// the replicas are spread over the nodes and the zones, unless the placement is set by the subscription
+	if config.Replicas > 1 {
+		podSpec := &deploymentSpec.Template.Spec
+		if podSpec.Affinity == nil {
+			podSpec.Affinity = &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+							TopologyKey:   corev1.LabelHostname,
+						},
+					}},
+				},
+			}
+		}
+		if config.TopologySpreadKey != "" {
+			podSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       config.TopologySpreadKey,
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+			}}
+		}
+	}
+
+	// the TLS config of the TLSProfile rolls out the pod on change, oauth-proxy does not reload it
+	serverContainer := &deploymentSpec.Template.Spec.Containers[0]
+	serverContainer.Env = append(serverContainer.Env, GetTLSConfigEnv(serverTLSConfig)...)
+	proxyContainer := &deploymentSpec.Template.Spec.Containers[1]
+	proxyContainer.Args = append(proxyContainer.Args, GetOAuthProxyTLSArgs(proxyTLSConfig)...)
+
 	deployment.Spec = *deploymentSpec
 	return deployment
 }
 
+// getUXBackendServerPodDisruptionBudget returns the PodDisruptionBudget keeping a ux-backend pod available during
+// node drains. It is only desired with more than one replica, as it would block the drains otherwise.
+func getUXBackendServerPodDisruptionBudget() *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{}
+	pdb.Name = "ux-backend-server"
+	pdb.Namespace = OperatorNamespace
+	pdb.Spec = policyv1.PodDisruptionBudgetSpec{
+		MinAvailable: ptr.To(intstr.FromInt32(1)),
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "ux-backend-server"},
+		},
+	}
+	return pdb
+}
+
 func getUXBackendServerSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -275,3 +317,12 @@ func getUXBackendServerNetworkPolicy() *networkingv1.NetworkPolicy {
 		},
 	}
 }
+
+// getOAuthProxyDelegateURLs returns the delegated authorization of oauth-proxy, which only checks the access to the
+// paths. The handlers of ux-backend check the access to the resources they touch on behalf of the user.
+func getOAuthProxyDelegateURLs(namespace string) string {
+	return fmt.Sprintf(`{"/":{"group":"ocs.openshift.io","resource":"storageclusters","namespace":%q,"verb":"create"},`+
+		`"/info/":{"group":"authorization.k8s.io","resource":"selfsubjectaccessreviews","verb":"create"},`+
+		`"/v1/info/":{"group":"authorization.k8s.io","resource":"selfsubjectaccessreviews","verb":"create"},`+
+		`"/v1/openapi.yaml":{"group":"authorization.k8s.io","resource":"selfsubjectaccessreviews","verb":"create"}}`, namespace)
+}
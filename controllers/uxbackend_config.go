@@ -0,0 +1,158 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/red-hat-storage/odf-operator/pkg/util"
+)
+
+const (
+	// uxBackendTopologySpreadDisabled disables the topology spread of the ux-backend pods when set as its key
+	uxBackendTopologySpreadDisabled = "off"
+)
+
+// UXBackendConfig is the placement, availability and resources of the ux-backend-server deployment
+type UXBackendConfig struct {
+	Replicas          int32
+	TopologySpreadKey string
+	NodeSelector      map[string]string
+	Affinity          *corev1.Affinity
+	Tolerations       []corev1.Toleration
+	ServerResources   corev1.ResourceRequirements
+	ProxyResources    corev1.ResourceRequirements
+}
+
+// DefaultUXBackendConfig returns the config of the ux-backend-server deployment for the cluster topology. A single
+// replica runs on the single replica clusters, two replicas spread over the nodes and zones run on the others.
+func DefaultUXBackendConfig(singleReplica bool) UXBackendConfig {
+
+	config := UXBackendConfig{
+		Replicas:          2,
+		TopologySpreadKey: corev1.LabelTopologyZone,
+		ServerResources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("250m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+		ProxyResources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("5m"),
+				corev1.ResourceMemory: resource.MustParse("50Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("25m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+	}
+
+	if singleReplica {
+		config.Replicas = 1
+	}
+
+	return config
+}
+
+// GetUXBackendConfig returns the config of the ux-backend-server deployment. The node selector, affinity and
+// tolerations follow the config of the odf-operator subscription, the other settings of the operator configuration
+// are applied on top of the defaults of the cluster topology. Invalid settings are logged and the defaults are kept.
+func GetUXBackendConfig(ctx context.Context, cli client.Client, logger logr.Logger, odfSub *opv1a1.Subscription) (UXBackendConfig, error) {
+
+	singleReplica, err := util.IsSingleReplicaTopology(ctx, cli)
+	if err != nil {
+		return UXBackendConfig{}, fmt.Errorf("failed to get the cluster topology: %w", err)
+	}
+
+	config := DefaultUXBackendConfig(singleReplica)
+
+	if odfSub != nil && odfSub.Spec != nil && odfSub.Spec.Config != nil {
+		config.Tolerations = odfSub.Spec.Config.Tolerations
+		config.NodeSelector = odfSub.Spec.Config.NodeSelector
+		config.Affinity = odfSub.Spec.Config.Affinity
+	}
+
+	if value := os.Getenv("UX_BACKEND_REPLICAS"); value != "" {
+		if replicas, err := strconv.ParseInt(value, 10, 32); err != nil || replicas < 1 {
+			logger.Error(fmt.Errorf("invalid value %q", value), "ignoring UX_BACKEND_REPLICAS")
+		} else {
+			config.Replicas = int32(replicas)
+		}
+	}
+
+	if value := os.Getenv("UX_BACKEND_TOPOLOGY_SPREAD_KEY"); value == uxBackendTopologySpreadDisabled {
+		config.TopologySpreadKey = ""
+	} else if value != "" {
+		config.TopologySpreadKey = value
+	}
+
+	config.ServerResources = mergeResourceRequirements(logger, "UX_BACKEND_SERVER_RESOURCES", config.ServerResources)
+	config.ProxyResources = mergeResourceRequirements(logger, "UX_BACKEND_PROXY_RESOURCES", config.ProxyResources)
+
+	return config, nil
+}
+
+// mergeResourceRequirements applies the requests and limits set as JSON in the env variable on top of the defaults
+func mergeResourceRequirements(logger logr.Logger, name string, defaults corev1.ResourceRequirements) corev1.ResourceRequirements {
+
+	value := os.Getenv(name)
+	if value == "" {
+		return defaults
+	}
+
+	override := corev1.ResourceRequirements{}
+	if err := json.Unmarshal([]byte(value), &override); err != nil {
+		logger.Error(err, "ignoring "+name)
+		return defaults
+	}
+
+	merged := *defaults.DeepCopy()
+	if merged.Requests == nil {
+		merged.Requests = corev1.ResourceList{}
+	}
+	if merged.Limits == nil {
+		merged.Limits = corev1.ResourceList{}
+	}
+	maps.Copy(merged.Requests, override.Requests)
+	maps.Copy(merged.Limits, override.Limits)
+
+	for resourceName, request := range merged.Requests {
+		if limit, ok := merged.Limits[resourceName]; ok && request.Cmp(limit) > 0 {
+			logger.Error(fmt.Errorf("%s request %s exceeds the limit %s", resourceName, request.String(), limit.String()), "ignoring "+name)
+			return defaults
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newUXBackendConfigTestClient(topology configv1.TopologyMode) *fake.ClientBuilder {
+	scheme := newTestScheme()
+	utilruntime.Must(configv1.AddToScheme(scheme))
+
+	infrastructure := &configv1.Infrastructure{}
+	infrastructure.Name = "cluster"
+	infrastructure.Status.InfrastructureTopology = topology
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(infrastructure)
+}
+
+func TestGetUXBackendConfig(t *testing.T) {
+	ctx := context.Background()
+
+	cli := newUXBackendConfigTestClient(configv1.SingleReplicaTopologyMode).Build()
+	config, err := GetUXBackendConfig(ctx, cli, testLogger, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultUXBackendConfig(true), config)
+	assert.Equal(t, int32(1), config.Replicas)
+
+	odfSub := &opv1a1.Subscription{Spec: &opv1a1.SubscriptionSpec{Config: &opv1a1.SubscriptionConfig{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:  []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Effect: corev1.TaintEffectNoSchedule}},
+	}}}
+
+	t.Setenv("UX_BACKEND_REPLICAS", "3")
+	t.Setenv("UX_BACKEND_TOPOLOGY_SPREAD_KEY", "off")
+	t.Setenv("UX_BACKEND_SERVER_RESOURCES", `{"limits":{"memory":"1Gi"}}`)
+
+	cli = newUXBackendConfigTestClient(configv1.HighlyAvailableTopologyMode).Build()
+	config, err = GetUXBackendConfig(ctx, cli, testLogger, odfSub)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), config.Replicas)
+	assert.Empty(t, config.TopologySpreadKey)
+	assert.Equal(t, odfSub.Spec.Config.NodeSelector, config.NodeSelector)
+	assert.Equal(t, odfSub.Spec.Config.Tolerations, config.Tolerations)
+	assert.Equal(t, resource.MustParse("1Gi"), config.ServerResources.Limits[corev1.ResourceMemory])
+	assert.Equal(t, resource.MustParse("250m"), config.ServerResources.Limits[corev1.ResourceCPU])
+
+	// invalid values keep the defaults
+	t.Setenv("UX_BACKEND_REPLICAS", "0")
+	t.Setenv("UX_BACKEND_TOPOLOGY_SPREAD_KEY", "")
+	t.Setenv("UX_BACKEND_SERVER_RESOURCES", `{"requests":{"cpu":"1"}}`)
+	t.Setenv("UX_BACKEND_PROXY_RESOURCES", "50Mi")
+
+	config, err = GetUXBackendConfig(ctx, cli, testLogger, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultUXBackendConfig(false), config)
+}
+
+func TestGetUXBackendServerDeployment(t *testing.T) {
+	config := DefaultUXBackendConfig(true)
+	deployment := getUXBackendServerDeployment(config, "session_secret", nil, nil)
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, int32(1), *deployment.Spec.Replicas)
+	assert.Nil(t, podSpec.Affinity)
+	assert.Empty(t, podSpec.TopologySpreadConstraints)
+	assert.Len(t, podSpec.Tolerations, 1)
+
+	config = DefaultUXBackendConfig(false)
+	deployment = getUXBackendServerDeployment(config, "session_secret", nil, nil)
+	podSpec = deployment.Spec.Template.Spec
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	assert.Equal(t, int32(0), deployment.Spec.Strategy.RollingUpdate.MaxUnavailable.IntVal)
+	assert.Equal(t, corev1.LabelHostname,
+		podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey)
+	assert.Equal(t, corev1.LabelTopologyZone, podSpec.TopologySpreadConstraints[0].TopologyKey)
+	assert.Equal(t, config.ServerResources, podSpec.Containers[0].Resources)
+	assert.Equal(t, config.ProxyResources, podSpec.Containers[1].Resources)
+
+	// the affinity of the subscription is kept
+	config.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+	deployment = getUXBackendServerDeployment(config, "session_secret", nil, nil)
+	assert.Equal(t, config.Affinity, deployment.Spec.Template.Spec.Affinity)
+}
+
+func TestGetOAuthProxyDelegateURLs(t *testing.T) {
+	delegateURLs := map[string]map[string]string{}
+	assert.NoError(t, json.Unmarshal([]byte(getOAuthProxyDelegateURLs("odf-storage")), &delegateURLs))
+	assert.Equal(t, "odf-storage", delegateURLs["/"]["namespace"])
+	for _, path := range []string{"/info/", "/v1/info/", "/v1/openapi.yaml"} {
+		assert.Equal(t, "selfsubjectaccessreviews", delegateURLs[path]["resource"])
+	}
+
+	args := getUXBackendServerDeployment(DefaultUXBackendConfig(true), "session_secret", nil, nil).Spec.Template.Spec.Containers[1].Args
+	assert.Contains(t, args, "-openshift-delegate-urls="+getOAuthProxyDelegateURLs(OperatorNamespace))
+	assert.Contains(t, args, "-pass-access-token=true")
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/red-hat-storage/odf-operator/metrics"
+)
+
+const (
+	rotatedAtAnnotationKey        = "odf.openshift.io/rotated-at"
+	sessionSecretKeyAnnotationKey = "odf.openshift.io/session-secret-key"
+	// expiresAtAnnotationKey is set by the issuer of a secret which is not rotated by odf-operator
+	expiresAtAnnotationKey = "odf.openshift.io/expires-at"
+	// uxCertNotBeforeAnnotationKey rolls out the ux-backend pods when the serving certificate is reissued
+	uxCertNotBeforeAnnotationKey = "odf.openshift.io/ux-cert-not-before"
+
+	sessionSecretKeyPrefix   = "session_secret"
+	onboardingPrivateKeyName = "onboarding-private-key"
+
+	// secretRotationDisabled disables the time based rotation when set as the interval
+	secretRotationDisabled = "off"
+)
+
+// SecretRotationConfig is the time based rotation of the secrets of the ux-backend server
+type SecretRotationConfig struct {
+	// Interval is the age at which the secrets are rotated, the time based rotation is disabled when zero
+	Interval time.Duration
+	// Overlap is the minimum time the previous session secrets are kept after a rotation, they are also kept until
+	// the ux-backend pods are rolled out to the new session secret
+	Overlap time.Duration
+	// ExpiryWarning is the time before the expiry, or the due rotation, at which warning events are recorded
+	ExpiryWarning time.Duration
+}
+
+// DefaultSecretRotationConfig returns the default rotation config, which rotates the secrets every 90 days
+func DefaultSecretRotationConfig() SecretRotationConfig {
+	return SecretRotationConfig{
+		Interval:      90 * 24 * time.Hour,
+		Overlap:       time.Hour,
+		ExpiryWarning: 14 * 24 * time.Hour,
+	}
+}
+
+// GetSecretRotationConfig returns the rotation config with the settings of the operator configuration applied on top
+// of the defaults. Invalid settings are logged and the defaults are kept.
+func GetSecretRotationConfig(logger logr.Logger) SecretRotationConfig {
+
+	config := DefaultSecretRotationConfig()
+
+	if value := os.Getenv("UX_BACKEND_SECRET_ROTATION_INTERVAL"); value == secretRotationDisabled {
+		config.Interval = 0
+	} else if value != "" {
+		if interval, err := time.ParseDuration(value); err != nil || interval < time.Hour {
+			logger.Error(fmt.Errorf("invalid value %q, the interval is at least 1h", value), "ignoring UX_BACKEND_SECRET_ROTATION_INTERVAL")
+		} else {
+			config.Interval = interval
+		}
+	}
+
+	if value := os.Getenv("UX_BACKEND_SECRET_ROTATION_OVERLAP"); value != "" {
+		if overlap, err := time.ParseDuration(value); err != nil || overlap < 0 {
+			logger.Error(fmt.Errorf("invalid value %q", value), "ignoring UX_BACKEND_SECRET_ROTATION_OVERLAP")
+		} else {
+			config.Overlap = overlap
+		}
+	}
+
+	if value := os.Getenv("UX_BACKEND_SECRET_EXPIRY_WARNING"); value != "" {
+		if warning, err := time.ParseDuration(value); err != nil || warning < 0 {
+			logger.Error(fmt.Errorf("invalid value %q", value), "ignoring UX_BACKEND_SECRET_EXPIRY_WARNING")
+		} else {
+			config.ExpiryWarning = warning
+		}
+	}
+
+	return config
+}
+
+// rotateSessionSecret rotates the session secret of oauth-proxy when the ODF version changes or the secret is older than
+// the rotation interval. Every generation of the session secret is stored under its own key, so the pods of the previous
+// rollout keep reading theirs. The previous keys are removed once the overlap is over and rolledOut reports that the
+// pods are rolled out to the current key. It returns true if the secret was rotated.
+func rotateSessionSecret(secret *corev1.Secret, odfVersion string, now time.Time, config SecretRotationConfig, rolledOut bool) (bool, error) {
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	currentKey := secret.Annotations[sessionSecretKeyAnnotationKey]
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[rotatedAtAnnotationKey])
+
+	rotate := currentKey == "" || len(secret.Data[currentKey]) == 0 || err != nil ||
+		secret.Annotations[rotatedVersionAnnotationKey] != odfVersion ||
+		(config.Interval > 0 && !now.Before(rotatedAt.Add(config.Interval)))
+
+	if !rotate {
+		// the deployments of the previous versions read the session_secret key, which is removed the same way
+		if rolledOut && !now.Before(rotatedAt.Add(config.Overlap)) {
+			for key := range secret.Data {
+				if key != currentKey && strings.HasPrefix(key, sessionSecretKeyPrefix) {
+					delete(secret.Data, key)
+				}
+			}
+		}
+		return false, nil
+	}
+
+	sessionSecret, err := generateSessionSecret()
+	if err != nil {
+		return false, fmt.Errorf("failed to generate session secret: %w", err)
+	}
+
+	currentKey = fmt.Sprintf("%s_%d", sessionSecretKeyPrefix, now.Unix())
+	secret.Data[currentKey] = []byte(sessionSecret)
+	secret.Annotations[sessionSecretKeyAnnotationKey] = currentKey
+	secret.Annotations[rotatedAtAnnotationKey] = now.UTC().Format(time.RFC3339)
+	secret.Annotations[rotatedVersionAnnotationKey] = odfVersion
+
+	return true, nil
+}
+
+// getSessionSecretNextRotation returns the time of the next time based rotation of the session secret
+func getSessionSecretNextRotation(secret *corev1.Secret, config SecretRotationConfig) (time.Time, bool) {
+
+	if config.Interval == 0 {
+		return time.Time{}, false
+	}
+
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[rotatedAtAnnotationKey])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return rotatedAt.Add(config.Interval), true
+}
+
+// getSessionSecretOverlapEnd returns the end of the overlap of the previous session secrets, if any are kept
+func getSessionSecretOverlapEnd(secret *corev1.Secret, config SecretRotationConfig) (time.Time, bool) {
+
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[rotatedAtAnnotationKey])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	for key := range secret.Data {
+		if key != secret.Annotations[sessionSecretKeyAnnotationKey] && strings.HasPrefix(key, sessionSecretKeyPrefix) {
+			return rotatedAt.Add(config.Overlap), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// isDeploymentRolledOut returns true if all the pods of the deployment run its current template
+func isDeploymentRolledOut(deployment *appsv1.Deployment) bool {
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// isUXBackendServerRolledOut returns true if the pods of the ux-backend server are rolled out to its current template,
+// and no longer read the previous session secrets
+func (r *ClusterVersionReconciler) isUXBackendServerRolledOut(ctx context.Context) (bool, error) {
+
+	deployment := &appsv1.Deployment{}
+	deployment.Name = "ux-backend-server"
+	deployment.Namespace = OperatorNamespace
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return isDeploymentRolledOut(deployment), nil
+}
+
+// getCertificateValidity returns the validity of the first certificate of the PEM data
+func getCertificateValidity(data []byte) (notBefore, notAfter time.Time, err error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, time.Time{}, fmt.Errorf("no PEM certificate found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return cert.NotBefore, cert.NotAfter, nil
+}
+
+// secretExpiry is the time at which a secret of the ux-backend server expires or is due for rotation
+type secretExpiry struct {
+	secret *corev1.Secret
+	expiry time.Time
+}
+
+// report reports the expiry in the metrics and records a warning event when it is within the warning window
+func (e secretExpiry) report(recorder events.EventRecorder, now time.Time, config SecretRotationConfig) {
+
+	metrics.ReportUXBackendSecretExpiry(e.secret.Name, e.expiry)
+
+	if recorder == nil || now.Before(e.expiry.Add(-config.ExpiryWarning)) {
+		return
+	}
+
+	if now.Before(e.expiry) {
+		recorder.Eventf(e.secret, nil, corev1.EventTypeWarning, "SecretExpiringSoon", "Monitor",
+			"Secret %s expires or is due for rotation at %s", e.secret.Name, e.expiry.UTC().Format(time.RFC3339))
+	} else {
+		recorder.Eventf(e.secret, nil, corev1.EventTypeWarning, "SecretExpired", "Monitor",
+			"Secret %s expired or was due for rotation at %s", e.secret.Name, e.expiry.UTC().Format(time.RFC3339))
+	}
+}
+
+// ensureUXCertRotation checks the serving certificate of the ux-backend server. The certificate is deleted once it is
+// older than the rotation interval, so that the service CA operator issues a new one. It returns the notBefore of the
+// certificate, which rolls out the pods when the certificate is reissued, and the expiry to monitor.
+func (r *ClusterVersionReconciler) ensureUXCertRotation(ctx context.Context, logger logr.Logger, now time.Time,
+	config SecretRotationConfig) (string, *secretExpiry, error) {
+
+	certSecret := &corev1.Secret{}
+	certSecret.Name = uxCertName
+	certSecret.Namespace = OperatorNamespace
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(certSecret), certSecret); err != nil {
+		// the certificate is not issued yet
+		return "", nil, client.IgnoreNotFound(err)
+	}
+
+	notBefore, notAfter, err := getCertificateValidity(certSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		logger.Error(err, "failed to read the UX backend serving certificate")
+		return "", nil, nil
+	}
+
+	expiry := notAfter
+	if config.Interval > 0 {
+		if rotationDue := notBefore.Add(config.Interval); rotationDue.Before(expiry) {
+			expiry = rotationDue
+		}
+		if !now.Before(notBefore.Add(config.Interval)) {
+			logger.Info("Rotating the UX backend serving certificate", "notBefore", notBefore)
+			// the precondition keeps a certificate reissued in the meantime
+			if err := r.Client.Delete(ctx, certSecret, client.Preconditions{UID: &certSecret.UID}); client.IgnoreNotFound(err) != nil {
+				return "", nil, fmt.Errorf("failed to delete UX backend serving certificate: %w", err)
+			}
+			metrics.ReportUXBackendSecretRotation(certSecret.Name)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(certSecret, nil, corev1.EventTypeNormal, "SecretRotated", "Rotate",
+					"Secret %s was deleted to be reissued by the service CA", certSecret.Name)
+			}
+		}
+	}
+
+	return notBefore.UTC().Format(time.RFC3339), &secretExpiry{secret: certSecret, expiry: expiry}, nil
+}
+
+// getOnboardingPrivateKeyExpiry returns the expiry of the onboarding private key. The key is issued and rotated by
+// ocs-operator, it is only monitored when its issuer sets the expiry annotation.
+func (r *ClusterVersionReconciler) getOnboardingPrivateKeyExpiry(ctx context.Context, logger logr.Logger) (*secretExpiry, error) {
+
+	privateKey := &corev1.Secret{}
+	privateKey.Name = onboardingPrivateKeyName
+	privateKey.Namespace = OperatorNamespace
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(privateKey), privateKey); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	value, ok := privateKey.Annotations[expiresAtAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logger.Error(err, "ignoring the invalid expiry of the onboarding private key", "annotation", expiresAtAnnotationKey)
+		return nil, nil
+	}
+
+	return &secretExpiry{secret: privateKey, expiry: expiresAt}, nil
+}
+
+// getRequeueAfter returns the time until the earliest of the times after now, or zero if there is none
+func getRequeueAfter(now time.Time, times ...time.Time) time.Duration {
+
+	var requeueAfter time.Duration
+	for _, t := range times {
+		if !t.After(now) {
+			continue
+		}
+		if after := t.Sub(now); requeueAfter == 0 || after < requeueAfter {
+			requeueAfter = after
+		}
+	}
+
+	return requeueAfter
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"maps"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestCertificate(t *testing.T, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notBefore, NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestGetSecretRotationConfig(t *testing.T) {
+	assert.Equal(t, DefaultSecretRotationConfig(), GetSecretRotationConfig(testLogger))
+
+	t.Setenv("UX_BACKEND_SECRET_ROTATION_INTERVAL", "off")
+	t.Setenv("UX_BACKEND_SECRET_ROTATION_OVERLAP", "0s")
+	t.Setenv("UX_BACKEND_SECRET_EXPIRY_WARNING", "72h")
+	config := GetSecretRotationConfig(testLogger)
+	assert.Equal(t, time.Duration(0), config.Interval)
+	assert.Equal(t, time.Duration(0), config.Overlap)
+	assert.Equal(t, 72*time.Hour, config.ExpiryWarning)
+
+	// invalid values keep the defaults
+	t.Setenv("UX_BACKEND_SECRET_ROTATION_INTERVAL", "1m")
+	t.Setenv("UX_BACKEND_SECRET_ROTATION_OVERLAP", "soon")
+	t.Setenv("UX_BACKEND_SECRET_EXPIRY_WARNING", "-1h")
+	assert.Equal(t, DefaultSecretRotationConfig(), GetSecretRotationConfig(testLogger))
+}
+
+func TestRotateSessionSecret(t *testing.T) {
+	config := DefaultSecretRotationConfig()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// the secret of a previous version uses the session_secret key
+	secret := getUXBackendServerSecret()
+	secret.Data = map[string][]byte{"session_secret": []byte("previous")}
+	secret.Annotations = map[string]string{rotatedVersionAnnotationKey: "4.21"}
+
+	rotated, err := rotateSessionSecret(secret, "4.22", now, config, true)
+	assert.NoError(t, err)
+	assert.True(t, rotated)
+	firstKey := secret.Annotations[sessionSecretKeyAnnotationKey]
+	assert.NotEqual(t, "session_secret", firstKey)
+	assert.Contains(t, secret.Data, "session_secret")
+	assert.Equal(t, "4.22", secret.Annotations[rotatedVersionAnnotationKey])
+
+	next, ok := getSessionSecretNextRotation(secret, config)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(config.Interval), next)
+	overlapEnd, ok := getSessionSecretOverlapEnd(secret, config)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(config.Overlap), overlapEnd)
+
+	// the previous session secret is kept during the overlap
+	rotated, err = rotateSessionSecret(secret, "4.22", now.Add(time.Minute), config, true)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Len(t, secret.Data, 2)
+
+	// the previous session secret is kept until the pods are rolled out
+	rotated, err = rotateSessionSecret(secret, "4.22", now.Add(config.Overlap), config, false)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Len(t, secret.Data, 2)
+
+	rotated, err = rotateSessionSecret(secret, "4.22", now.Add(config.Overlap), config, true)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Equal(t, []string{firstKey}, slices.Collect(maps.Keys(secret.Data)))
+	_, ok = getSessionSecretOverlapEnd(secret, config)
+	assert.False(t, ok)
+
+	// the session secret is rotated after the interval, the previous generation is kept for the overlap
+	now = now.Add(config.Interval)
+	rotated, err = rotateSessionSecret(secret, "4.22", now, config, true)
+	assert.NoError(t, err)
+	assert.True(t, rotated)
+	assert.NotEqual(t, firstKey, secret.Annotations[sessionSecretKeyAnnotationKey])
+	assert.Len(t, secret.Data, 2)
+	assert.Contains(t, secret.Data, firstKey)
+
+	// the time based rotation is disabled
+	config.Interval = 0
+	rotated, err = rotateSessionSecret(secret, "4.22", now.Add(365*24*time.Hour), config, true)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Len(t, secret.Data, 1)
+	_, ok = getSessionSecretNextRotation(secret, config)
+	assert.False(t, ok)
+}
+
+func TestIsDeploymentRolledOut(t *testing.T) {
+	deployment := &appsv1.Deployment{}
+	deployment.Generation = 2
+	deployment.Spec.Replicas = ptr.To(int32(2))
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	assert.True(t, isDeploymentRolledOut(deployment))
+
+	// the new template is not observed yet
+	deployment.Generation = 3
+	assert.False(t, isDeploymentRolledOut(deployment))
+
+	// a pod of the previous template is still running
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2}
+	assert.False(t, isDeploymentRolledOut(deployment))
+}
+
+func TestEnsureUXCertRotation(t *testing.T) {
+	config := DefaultSecretRotationConfig()
+	now := time.Now()
+	notBefore := now.Add(-config.Interval + 24*time.Hour).Truncate(time.Second)
+
+	certSecret := &corev1.Secret{}
+	certSecret.Name = uxCertName
+	certSecret.Namespace = OperatorNamespace
+	certSecret.Data = map[string][]byte{corev1.TLSCertKey: newTestCertificate(t, notBefore, now.Add(365*24*time.Hour))}
+
+	recorder := events.NewFakeRecorder(10)
+	r := &ClusterVersionReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(certSecret).Build(),
+		Recorder: recorder,
+	}
+
+	ctx := context.Background()
+	certNotBefore, expiry, err := r.ensureUXCertRotation(ctx, testLogger, now, config)
+	assert.NoError(t, err)
+	assert.Equal(t, notBefore.UTC().Format(time.RFC3339), certNotBefore)
+	assert.True(t, notBefore.Add(config.Interval).Equal(expiry.expiry))
+
+	// the rotation is due in a day, which is within the warning window
+	expiry.report(recorder, now, config)
+	assert.Contains(t, <-recorder.Events, "SecretExpiringSoon")
+
+	// the certificate is deleted to be reissued once the rotation is due
+	_, _, err = r.ensureUXCertRotation(ctx, testLogger, now.Add(24*time.Hour), config)
+	assert.NoError(t, err)
+	assert.Contains(t, <-recorder.Events, "SecretRotated")
+	err = r.Client.Get(ctx, client.ObjectKeyFromObject(certSecret), &corev1.Secret{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// the certificate is not issued yet
+	certNotBefore, expiry, err = r.ensureUXCertRotation(ctx, testLogger, now, config)
+	assert.NoError(t, err)
+	assert.Empty(t, certNotBefore)
+	assert.Nil(t, expiry)
+}
+
+func TestGetOnboardingPrivateKeyExpiry(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+
+	newPrivateKey := func(annotations map[string]string) *corev1.Secret {
+		privateKey := &corev1.Secret{}
+		privateKey.Name = onboardingPrivateKeyName
+		privateKey.Namespace = OperatorNamespace
+		privateKey.Annotations = annotations
+		return privateKey
+	}
+
+	testCases := []struct {
+		label      string
+		privateKey *corev1.Secret
+		expect     bool
+	}{
+		{
+			label: "key does not exist",
+		},
+		{
+			label:      "key without expiry",
+			privateKey: newPrivateKey(map[string]string{rotatedAtAnnotationKey: "2020-01-01T00:00:00Z"}),
+		},
+		{
+			label:      "key with an invalid expiry",
+			privateKey: newPrivateKey(map[string]string{expiresAtAnnotationKey: "soon"}),
+		},
+		{
+			label:      "key with an expiry",
+			privateKey: newPrivateKey(map[string]string{expiresAtAnnotationKey: expiresAt.UTC().Format(time.RFC3339)}),
+			expect:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(newTestScheme())
+			if tc.privateKey != nil {
+				builder = builder.WithObjects(tc.privateKey)
+			}
+			r := &ClusterVersionReconciler{Client: builder.Build()}
+
+			expiry, err := r.getOnboardingPrivateKeyExpiry(ctx, testLogger)
+			assert.NoError(t, err)
+			if !tc.expect {
+				assert.Nil(t, expiry)
+				return
+			}
+			if assert.NotNil(t, expiry) {
+				assert.True(t, expiresAt.Equal(expiry.expiry))
+			}
+		})
+	}
+}
+
+func TestGetRequeueAfter(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), getRequeueAfter(now))
+	assert.Equal(t, time.Duration(0), getRequeueAfter(now, now.Add(-time.Hour)))
+	assert.Equal(t, time.Hour, getRequeueAfter(now, now.Add(-time.Minute), now.Add(2*time.Hour), now.Add(time.Hour)))
+}
@@ -30,7 +30,10 @@ import (
 )
 
 const (
-	CsvWebhookPath = "/mutate-operators-coreos-com-v1alpha1-csv"
+	CsvWebhookPath          = "/mutate-operators-coreos-com-v1alpha1-csv"
+	SubscriptionWebhookPath = "/validate-operators-coreos-com-v1alpha1-subscription"
+
+	SubscriptionConfigWebhookPath = "/mutate-operators-coreos-com-v1alpha1-subscription"
 )
 
 var (
@@ -54,32 +57,120 @@ var (
 		},
 	}
 
-	csvWebhook = admrv1.MutatingWebhook{
-		Name: "csv.odf.openshift.io",
-		ClientConfig: admrv1.WebhookClientConfig{
-			Service: &admrv1.ServiceReference{
-				Name: csvWebhookService.Name,
-				Path: ptr.To(CsvWebhookPath),
-				Port: ptr.To(int32(443)),
+	csvWebhook = webhookSpec{
+		name: "csv.odf.openshift.io",
+		path: CsvWebhookPath,
+		rules: []admrv1.RuleWithOperations{{
+			Rule: admrv1.Rule{
+				APIGroups:   []string{"operators.coreos.com"},
+				APIVersions: []string{"v1alpha1"},
+				Resources:   []string{"clusterserviceversions"},
+				Scope:       ptr.To(admrv1.NamespacedScope),
+			},
+			Operations: []admrv1.OperationType{admrv1.Create, admrv1.Update},
+		}},
+		timeoutSeconds: 30,
+		// fail the admission if webhook can't be reached, a new csv must not start scaled up
+		// the handler allows updates on internal errors, the operator scaler repairs them later
+		failurePolicy: admrv1.Fail,
+		objectSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "olm.copiedFrom",
+					Operator: metav1.LabelSelectorOpDoesNotExist,
+				},
+				{
+					Key:      "odf.openshift.io/odf-operator",
+					Operator: metav1.LabelSelectorOpDoesNotExist,
+				},
 			},
 		},
-		Rules: []admrv1.RuleWithOperations{{
+		/*// create cel rules
+		var conditions []string
+		for _, pkg := range PkgNames {
+			labelKey := fmt.Sprintf(CsvLabelKey, pkg, operatorNamespace)
+			conditions = append(conditions, fmt.Sprintf(`object.metadata.labels.contains("%s")`, labelKey))
+		}
+
+		wh.MatchConditions = []admrv1.MatchCondition{{
+			Name:       "has_at_least_one_target_label",
+			Expression: strings.Join(conditions, " || "),
+		}}*/
+	}
+
+	subscriptionWebhook = webhookSpec{
+		name: "subscription.odf.openshift.io",
+		path: SubscriptionWebhookPath,
+		rules: []admrv1.RuleWithOperations{{
 			Rule: admrv1.Rule{
 				APIGroups:   []string{"operators.coreos.com"},
 				APIVersions: []string{"v1alpha1"},
-				Resources:   []string{"clusterserviceversions"},
+				Resources:   []string{"subscriptions"},
+				Scope:       ptr.To(admrv1.NamespacedScope),
+			},
+			Operations: []admrv1.OperationType{admrv1.Create, admrv1.Update},
+		}},
+		timeoutSeconds: 10,
+		// the webhook only guards against manual edits, do not block the subscriptions if it can't be reached
+		failurePolicy: admrv1.Ignore,
+	}
+
+	subscriptionConfigWebhook = webhookSpec{
+		name: "subscription-config.odf.openshift.io",
+		path: SubscriptionConfigWebhookPath,
+		rules: []admrv1.RuleWithOperations{{
+			Rule: admrv1.Rule{
+				APIGroups:   []string{"operators.coreos.com"},
+				APIVersions: []string{"v1alpha1"},
+				Resources:   []string{"subscriptions"},
 				Scope:       ptr.To(admrv1.NamespacedScope),
 			},
 			Operations: []admrv1.OperationType{admrv1.Create},
 		}},
-		SideEffects:             ptr.To(admrv1.SideEffectClassNone),
-		TimeoutSeconds:          ptr.To(int32(30)),
-		AdmissionReviewVersions: []string{"v1"},
-		// fail the admission if webhook can't be reached
-		FailurePolicy: ptr.To(admrv1.Fail),
+		timeoutSeconds: 10,
+		// the subscription controller applies the same config later, do not block OLM if the webhook can't be reached
+		failurePolicy: admrv1.Ignore,
 	}
 )
 
+// webhookSpec describes a webhook served by the operator, it is registered in a webhook configuration
+// of the same name which only holds this webhook
+type webhookSpec struct {
+	name           string
+	path           string
+	rules          []admrv1.RuleWithOperations
+	timeoutSeconds int32
+	failurePolicy  admrv1.FailurePolicyType
+	objectSelector *metav1.LabelSelector
+}
+
+// clientConfig returns the client config sending the requests to the service running in the operator namespace
+func (s *webhookSpec) clientConfig(operatorNamespace string, caBundle []byte) admrv1.WebhookClientConfig {
+	return admrv1.WebhookClientConfig{
+		Service: &admrv1.ServiceReference{
+			Name:      csvWebhookService.Name,
+			Namespace: operatorNamespace,
+			Path:      ptr.To(s.path),
+			Port:      ptr.To(int32(443)),
+		},
+		// preserve the existing (injected) CA bundle if any
+		CABundle: caBundle,
+	}
+}
+
+// namespaceSelector only sends the requests received from the target namespaces
+func (s *webhookSpec) namespaceSelector(targetNamespaces []string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "kubernetes.io/metadata.name",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   targetNamespaces,
+			},
+		},
+	}
+}
+
 func reconcileCsvWebhook(ctx context.Context, cli client.Client, logger logr.Logger, operatorNamespace string, targetNamespaces []string) error {
 
 	if err := reconcileCsvMutatingWebhookService(ctx, cli, logger, operatorNamespace); err != nil {
@@ -87,11 +178,21 @@ func reconcileCsvWebhook(ctx context.Context, cli client.Client, logger logr.Log
 		return err
 	}
 
-	if err := reconcileCsvMutatingWebhookConfiguration(ctx, cli, logger, operatorNamespace, targetNamespaces); err != nil {
+	if err := reconcileMutatingWebhookConfiguration(ctx, cli, logger, &csvWebhook, operatorNamespace, targetNamespaces); err != nil {
 		logger.Error(err, "unable to register csv mutating webhook")
 		return err
 	}
 
+	if err := reconcileValidatingWebhookConfiguration(ctx, cli, logger, &subscriptionWebhook, operatorNamespace, targetNamespaces); err != nil {
+		logger.Error(err, "unable to register subscription validating webhook")
+		return err
+	}
+
+	if err := reconcileMutatingWebhookConfiguration(ctx, cli, logger, &subscriptionConfigWebhook, operatorNamespace, targetNamespaces); err != nil {
+		logger.Error(err, "unable to register subscription mutating webhook")
+		return err
+	}
+
 	return nil
 }
 
@@ -117,72 +218,74 @@ func reconcileCsvMutatingWebhookService(ctx context.Context, cli client.Client,
 	return nil
 }
 
-func reconcileCsvMutatingWebhookConfiguration(ctx context.Context, cli client.Client, logger logr.Logger, operatorNamespace string, targetNamespaces []string) error {
+func reconcileMutatingWebhookConfiguration(ctx context.Context, cli client.Client, logger logr.Logger, spec *webhookSpec, operatorNamespace string, targetNamespaces []string) error {
 
 	whConfig := &admrv1.MutatingWebhookConfiguration{}
-	whConfig.Name = csvWebhook.Name
+	whConfig.Name = spec.name
 
-	res, err := controllerutil.CreateOrUpdate(ctx, cli, whConfig, func() error {
+	return reconcileWebhookConfiguration(ctx, cli, logger, whConfig, func() {
 
-		if whConfig.Annotations == nil {
-			whConfig.Annotations = map[string]string{}
+		var caBundle []byte
+		if len(whConfig.Webhooks) > 0 {
+			// do not mutate CA bundle that was injected by openshift
+			caBundle = whConfig.Webhooks[0].ClientConfig.CABundle
 		}
-		// openshift fills in the ca on finding this annotation
-		whConfig.Annotations["service.beta.openshift.io/inject-cabundle"] = "true"
+
+		whConfig.Webhooks = []admrv1.MutatingWebhook{{
+			Name:                    spec.name,
+			ClientConfig:            spec.clientConfig(operatorNamespace, caBundle),
+			Rules:                   spec.rules,
+			FailurePolicy:           ptr.To(spec.failurePolicy),
+			NamespaceSelector:       spec.namespaceSelector(targetNamespaces),
+			ObjectSelector:          spec.objectSelector.DeepCopy(),
+			SideEffects:             ptr.To(admrv1.SideEffectClassNone),
+			TimeoutSeconds:          ptr.To(spec.timeoutSeconds),
+			AdmissionReviewVersions: []string{"v1"},
+		}}
+	})
+}
+
+func reconcileValidatingWebhookConfiguration(ctx context.Context, cli client.Client, logger logr.Logger, spec *webhookSpec, operatorNamespace string, targetNamespaces []string) error {
+
+	whConfig := &admrv1.ValidatingWebhookConfiguration{}
+	whConfig.Name = spec.name
+
+	return reconcileWebhookConfiguration(ctx, cli, logger, whConfig, func() {
 
 		var caBundle []byte
-		if len(whConfig.Webhooks) == 0 {
-			whConfig.Webhooks = make([]admrv1.MutatingWebhook, 1)
-		} else {
+		if len(whConfig.Webhooks) > 0 {
 			// do not mutate CA bundle that was injected by openshift
 			caBundle = whConfig.Webhooks[0].ClientConfig.CABundle
 		}
 
-		// webhook desired state
-		wh := &whConfig.Webhooks[0]
-		csvWebhook.DeepCopyInto(wh)
+		whConfig.Webhooks = []admrv1.ValidatingWebhook{{
+			Name:                    spec.name,
+			ClientConfig:            spec.clientConfig(operatorNamespace, caBundle),
+			Rules:                   spec.rules,
+			FailurePolicy:           ptr.To(spec.failurePolicy),
+			NamespaceSelector:       spec.namespaceSelector(targetNamespaces),
+			ObjectSelector:          spec.objectSelector.DeepCopy(),
+			SideEffects:             ptr.To(admrv1.SideEffectClassNone),
+			TimeoutSeconds:          ptr.To(spec.timeoutSeconds),
+			AdmissionReviewVersions: []string{"v1"},
+		}}
+	})
+}
 
-		wh.Name = whConfig.Name
-		// only send requests received from own namespace
-		wh.NamespaceSelector = &metav1.LabelSelector{
-			MatchExpressions: []metav1.LabelSelectorRequirement{
-				{
-					Key:      "kubernetes.io/metadata.name",
-					Operator: metav1.LabelSelectorOpIn,
-					Values:   targetNamespaces,
-				},
-			},
-		}
+// reconcileWebhookConfiguration creates or updates the webhook configuration, setWebhooks sets its desired webhooks
+func reconcileWebhookConfiguration(ctx context.Context, cli client.Client, logger logr.Logger, whConfig client.Object, setWebhooks func()) error {
 
-		wh.ObjectSelector = &metav1.LabelSelector{
-			MatchExpressions: []metav1.LabelSelectorRequirement{
-				{
-					Key:      "olm.copiedFrom",
-					Operator: metav1.LabelSelectorOpDoesNotExist,
-				},
-				{
-					Key:      "odf.openshift.io/odf-operator",
-					Operator: metav1.LabelSelectorOpDoesNotExist,
-				},
-			},
-		}
+	res, err := controllerutil.CreateOrUpdate(ctx, cli, whConfig, func() error {
 
-		/*// create cel rules
-		var conditions []string
-		for _, pkg := range PkgNames {
-			labelKey := fmt.Sprintf(CsvLabelKey, pkg, operatorNamespace)
-			conditions = append(conditions, fmt.Sprintf(`object.metadata.labels.contains("%s")`, labelKey))
+		annotations := whConfig.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
 		}
+		// openshift fills in the ca on finding this annotation
+		annotations["service.beta.openshift.io/inject-cabundle"] = "true"
+		whConfig.SetAnnotations(annotations)
 
-		wh.MatchConditions = []admrv1.MatchCondition{{
-			Name:       "has_at_least_one_target_label",
-			Expression: strings.Join(conditions, " || "),
-		}}*/
-
-		// preserve the existing (injected) CA bundle if any
-		wh.ClientConfig.CABundle = caBundle
-		// send request to the service running in own namespace
-		wh.ClientConfig.Service.Namespace = operatorNamespace
+		setWebhooks()
 
 		return nil
 	})
@@ -190,6 +293,6 @@ func reconcileCsvMutatingWebhookConfiguration(ctx context.Context, cli client.Cl
 		return err
 	}
 
-	logger.Info("successfully created or updated webhook", "operation", res, "name", whConfig.Name)
+	logger.Info("successfully created or updated webhook", "operation", res, "name", whConfig.GetName())
 	return nil
 }
@@ -0,0 +1,82 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admrv1 "k8s.io/api/admissionregistration/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileCsvWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := newTestScheme()
+	utilruntime.Must(admrv1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	targetNamespaces := []string{"openshift-storage", "ibm-spectrum-scale"}
+	assert.NoError(t, reconcileCsvWebhook(ctx, cli, testLogger, "openshift-storage", targetNamespaces))
+
+	csvConfig := &admrv1.MutatingWebhookConfiguration{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: csvWebhook.name}, csvConfig))
+	assert.Equal(t, "true", csvConfig.Annotations["service.beta.openshift.io/inject-cabundle"])
+	if assert.Len(t, csvConfig.Webhooks, 1) {
+		wh := csvConfig.Webhooks[0]
+		assert.Equal(t, csvWebhook.name, wh.Name)
+		assert.Equal(t, "openshift-storage", wh.ClientConfig.Service.Namespace)
+		assert.Equal(t, CsvWebhookPath, *wh.ClientConfig.Service.Path)
+		assert.Equal(t, admrv1.Fail, *wh.FailurePolicy)
+		assert.Equal(t, targetNamespaces, wh.NamespaceSelector.MatchExpressions[0].Values)
+		assert.NotNil(t, wh.ObjectSelector)
+	}
+
+	subConfig := &admrv1.ValidatingWebhookConfiguration{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: subscriptionWebhook.name}, subConfig))
+	if assert.Len(t, subConfig.Webhooks, 1) {
+		wh := subConfig.Webhooks[0]
+		assert.Equal(t, SubscriptionWebhookPath, *wh.ClientConfig.Service.Path)
+		assert.Equal(t, admrv1.Ignore, *wh.FailurePolicy)
+		assert.Nil(t, wh.ObjectSelector)
+	}
+
+	subMutatingConfig := &admrv1.MutatingWebhookConfiguration{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: subscriptionConfigWebhook.name}, subMutatingConfig))
+	if assert.Len(t, subMutatingConfig.Webhooks, 1) {
+		wh := subMutatingConfig.Webhooks[0]
+		assert.Equal(t, SubscriptionConfigWebhookPath, *wh.ClientConfig.Service.Path)
+		assert.Equal(t, []admrv1.OperationType{admrv1.Create}, wh.Rules[0].Operations)
+	}
+
+	// the CA bundles injected by openshift are preserved
+	csvConfig.Webhooks[0].ClientConfig.CABundle = []byte("csv-ca")
+	assert.NoError(t, cli.Update(ctx, csvConfig))
+	subConfig.Webhooks[0].ClientConfig.CABundle = []byte("sub-ca")
+	assert.NoError(t, cli.Update(ctx, subConfig))
+
+	assert.NoError(t, reconcileCsvWebhook(ctx, cli, testLogger, "openshift-storage", targetNamespaces))
+
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: csvWebhook.name}, csvConfig))
+	assert.Equal(t, []byte("csv-ca"), csvConfig.Webhooks[0].ClientConfig.CABundle)
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: subscriptionWebhook.name}, subConfig))
+	assert.Equal(t, []byte("sub-ca"), subConfig.Webhooks[0].ClientConfig.CABundle)
+}
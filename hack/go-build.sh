@@ -9,3 +9,4 @@ set -x
 
 go build -a -o ${GOBIN:-bin}/odf-operator main.go
 go build -a -o ${GOBIN:-bin}/ux-backend-server services/ux-backend/main.go
+go build -a -o ${GOBIN:-bin}/cli-downloads-server services/cli-downloads/main.go
@@ -60,6 +60,9 @@ IMG ?= $(IMAGE_REGISTRY)/$(REGISTRY_NAMESPACE)/$(IMAGE_NAME):$(IMAGE_TAG)
 DEVICEFINDER_IMAGE_NAME ?= devicefinder
 DEVICEFINDER_IMAGE ?= $(IMAGE_REGISTRY)/$(REGISTRY_NAMESPACE)/$(DEVICEFINDER_IMAGE_NAME):$(IMAGE_TAG)
 
+# ODF_CLI_IMAGE defines the image holding the odf CLI binaries served on disconnected clusters.
+ODF_CLI_IMAGE ?= quay.io/ocs-dev/odf-cli:latest
+
 # BUNDLE_IMG defines the image used for the bundle.
 BUNDLE_IMG ?= $(IMAGE_REGISTRY)/$(REGISTRY_NAMESPACE)/$(BUNDLE_IMAGE_NAME):$(IMAGE_TAG)
 
@@ -18,9 +18,20 @@ spec:
           value: $(UX_BACKEND_OAUTH_IMAGE)
         - name: DEVICEFINDER_IMAGE
           value: $(DEVICEFINDER_IMAGE)
+        - name: ODF_CLI_IMAGE
+          value: $(ODF_CLI_IMAGE)
+        - name: CLUSTER_CONNECTIVITY
         - name: ONBOARDING_TOKEN_LIFETIME
         - name: UX_BACKEND_PORT
         - name: TLS_ENABLED
+        - name: REJECT_CONFLICTING_SUBSCRIPTION_EDITS
+        - name: CONSOLE_HSTS_MAX_AGE
+        - name: CONSOLE_CONTENT_TYPE_NOSNIFF
+        - name: CONSOLE_CONTENT_SECURITY_POLICY
+        - name: CONSOLE_GZIP
+        - name: CONSOLE_ACCESS_LOG_FORMAT
+        - name: CONSOLE_CONNECTIONS_PER_CLIENT
+        - name: CONSOLE_PLUGIN_CSP_CONNECT_SRC
 endef
 export DEPLOYMENT_ENV_PATCH
 
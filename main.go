@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"os"
 
@@ -30,9 +31,12 @@ import (
 	opv1 "github.com/operator-framework/api/pkg/operators/v1"
 	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	opv2 "github.com/operator-framework/api/pkg/operators/v2"
+	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
 	admrv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	k8sscheme "k8s.io/client-go/kubernetes/scheme"
@@ -44,6 +48,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metrics "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/red-hat-storage/odf-operator/controllers"
@@ -66,20 +71,27 @@ func init() {
 	utilruntime.Must(admrv1.AddToScheme(scheme))
 	utilruntime.Must(extv1.AddToScheme(scheme))
 	utilruntime.Must(configv1.AddToScheme(scheme))
+	utilruntime.Must(ocstlsv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 func main() {
+	controllers.LoadEnv()
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var odfConsolePort int
+	var csvWebhookAuditSize int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8085", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8082", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.IntVar(&odfConsolePort, "odf-console-port", 9001, "The port where the ODF console server will be serving it's payload")
+	flag.IntVar(&csvWebhookAuditSize, "csv-webhook-audit-size", 0,
+		"The number of recent CSV admissions kept for the "+webhook.CsvAdmissionAuditPath+" endpoint of the metrics server. "+
+			"The audit is disabled when it is 0.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -93,19 +105,38 @@ func main() {
 		os.Exit(1)
 	}
 
-	cacheOptions, err := getCacheOptions()
+	setupClient, err := newSetupClient()
+	if err != nil {
+		setupLog.Error(err, "unable to create setup client")
+		os.Exit(1)
+	}
+
+	cacheOptions, err := getCacheOptions(setupClient)
 	if err != nil {
 		setupLog.Error(err, "unable to get cache options")
 		os.Exit(1)
 	}
 
+	// The TLS configs are loaded before the servers start, the ClusterVersion controller keeps them up to date
+	serverTLSConfigs := controllers.NewServerTLSConfigs()
+	tlsSource, err := controllers.GetTLSSource(context.Background(), setupClient, setupLog, true)
+	if err != nil {
+		setupLog.Error(err, "unable to get TLS source")
+		os.Exit(1)
+	}
+	serverTLSConfigs.Update(setupLog, tlsSource)
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metrics.Options{
 			BindAddress:    metricsAddr,
 			SecureServing:  true,
 			FilterProvider: filters.WithAuthenticationAndAuthorization,
+			TLSOpts:        []func(*tls.Config){serverTLSConfigs.TLSOpt(controllers.TLSServerMetrics)},
 		},
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			TLSOpts: []func(*tls.Config){serverTLSConfigs.TLSOpt(controllers.TLSServerWebhook)},
+		}),
 		HealthProbeBindAddress:  probeAddr,
 		LeaderElection:          enableLeaderElection,
 		LeaderElectionID:        "4fd470de.openshift.io",
@@ -118,9 +149,11 @@ func main() {
 	}
 
 	if err = (&controllers.ClusterVersionReconciler{
-		Client:      mgr.GetClient(),
-		Scheme:      mgr.GetScheme(),
-		ConsolePort: int32(odfConsolePort), //nolint:gosec
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		Recorder:         mgr.GetEventRecorder("odf-operator"),
+		ConsolePort:      int32(odfConsolePort), //nolint:gosec
+		ServerTLSConfigs: serverTLSConfigs,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterVersion")
 		os.Exit(1)
@@ -144,14 +177,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	csvAdmissionAudit := webhook.NewCsvAdmissionAudit(csvWebhookAuditSize)
+	if csvAdmissionAudit != nil {
+		if err := mgr.AddMetricsServerExtraHandler(webhook.CsvAdmissionAuditPath, csvAdmissionAudit); err != nil {
+			setupLog.Error(err, "unable to set up csv webhook audit endpoint")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&webhook.ClusterServiceVersionDeploymentScaler{
 		Client:            mgr.GetClient(),
 		Decoder:           admission.NewDecoder(mgr.GetScheme()),
 		OperatorNamespace: operatorNamespace,
+		Audit:             csvAdmissionAudit,
 	}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterServiceVersion")
 		os.Exit(1)
 	}
+
+	if err = (&webhook.SubscriptionValidator{
+		Client:  mgr.GetClient(),
+		Decoder: admission.NewDecoder(mgr.GetScheme()),
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Subscription")
+		os.Exit(1)
+	}
+
+	if err = (&webhook.SubscriptionConfigDefaulter{
+		Client:  mgr.GetClient(),
+		Decoder: admission.NewDecoder(mgr.GetScheme()),
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "SubscriptionConfig")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -171,22 +229,28 @@ func main() {
 	}
 }
 
-func getCacheOptions() (cache.Options, error) {
+// newSetupClient returns an uncached client for the reads needed before the manager starts
+func newSetupClient() (client.Client, error) {
 
 	// Obtain config (works in-cluster and with KUBECONFIG outside)
 	cfg, err := config.GetConfig()
 	if err != nil {
 		setupLog.Error(err, "error getting kubeconfig")
-		return cache.Options{}, err
+		return nil, err
 	}
 
 	// Create the client
 	cli, err := client.New(cfg, client.Options{Scheme: scheme})
 	if err != nil {
 		setupLog.Error(err, "error creating client")
-		return cache.Options{}, err
+		return nil, err
 	}
 
+	return cli, nil
+}
+
+func getCacheOptions(cli client.Client) (cache.Options, error) {
+
 	configmap, err := controllers.GetOdfConfigMap(context.Background(), cli, setupLog)
 	if err != nil {
 		setupLog.Error(err, "error getting configmap")
@@ -234,6 +298,13 @@ func getCacheOptions() (cache.Options, error) {
 					return crd, nil
 				},
 			},
+			// Only the ClusterRoles of the odf-operator CSVs are read, they own the console resources.
+			&rbacv1.ClusterRole{}: {
+				Label: labels.SelectorFromSet(labels.Set{
+					controllers.OlmOwnerKindLabel:      opv1a1.ClusterServiceVersionKind,
+					controllers.OlmOwnerNamespaceLabel: controllers.OperatorNamespace,
+				}),
+			},
 		},
 	}
 
@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -28,10 +30,51 @@ var (
 		Name:      "system_map",
 		Help:      "Map of ODF StorageSystems to their target Custom Resource",
 	}, []string{"storage_system", "target_name", "target_namespace", "target_kind", "target_apiversion"})
+
+	csvWebhookAdmissions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "odf",
+		Subsystem: "csv_webhook",
+		Name:      "admissions_total",
+		Help:      "Number of ClusterServiceVersion admission reviews handled by the CSV webhook by outcome",
+	}, []string{"operation", "outcome"})
+
+	csvWebhookAdmissionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "odf",
+		Subsystem: "csv_webhook",
+		Name:      "admission_duration_seconds",
+		Help:      "Time taken by the CSV webhook to handle a ClusterServiceVersion admission review",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 12),
+	}, []string{"operation"})
+
+	csvWebhookConfigReloads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "odf",
+		Subsystem: "csv_webhook",
+		Name:      "config_reloads_total",
+		Help:      "Number of times the CSV webhook reloaded the ODF operator configmap",
+	})
+
+	uxBackendSecretExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "odf",
+		Subsystem: "ux_backend",
+		Name:      "secret_expiry_timestamp_seconds",
+		Help:      "Time at which a secret of the ux-backend server expires or is due for rotation, in seconds since the epoch",
+	}, []string{"secret"})
+
+	uxBackendSecretRotations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "odf",
+		Subsystem: "ux_backend",
+		Name:      "secret_rotations_total",
+		Help:      "Number of rotations of the secrets of the ux-backend server",
+	}, []string{"secret"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(storageSystemMap)
+	metrics.Registry.MustRegister(csvWebhookAdmissions)
+	metrics.Registry.MustRegister(csvWebhookAdmissionDuration)
+	metrics.Registry.MustRegister(csvWebhookConfigReloads)
+	metrics.Registry.MustRegister(uxBackendSecretExpiry)
+	metrics.Registry.MustRegister(uxBackendSecretRotations)
 }
 
 func ReportODFSystemMapMetrics(storageSystem, name, namespace, kind, apiVersion string) {
@@ -43,3 +86,29 @@ func ReportODFSystemMapMetrics(storageSystem, name, namespace, kind, apiVersion
 		"target_apiversion": apiVersion,
 	}).Set(1)
 }
+
+func ReportCsvWebhookAdmission(operation, outcome string, duration time.Duration) {
+	csvWebhookAdmissions.With(prometheus.Labels{
+		"operation": operation,
+		"outcome":   outcome,
+	}).Inc()
+	csvWebhookAdmissionDuration.With(prometheus.Labels{
+		"operation": operation,
+	}).Observe(duration.Seconds())
+}
+
+func ReportCsvWebhookConfigReload() {
+	csvWebhookConfigReloads.Inc()
+}
+
+func ReportUXBackendSecretExpiry(secret string, expiry time.Time) {
+	uxBackendSecretExpiry.With(prometheus.Labels{
+		"secret": secret,
+	}).Set(float64(expiry.Unix()))
+}
+
+func ReportUXBackendSecretRotation(secret string) {
+	uxBackendSecretRotations.With(prometheus.Labels{
+		"secret": secret,
+	}).Inc()
+}
@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -68,3 +69,44 @@ func TestReportODFSystemMapMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestReportCsvWebhookAdmission(t *testing.T) {
+	ReportCsvWebhookAdmission("CREATE", "scaled_down", 5*time.Millisecond)
+	ReportCsvWebhookAdmission("CREATE", "carried_over", 5*time.Millisecond)
+	ReportCsvWebhookAdmission("UPDATE", "repaired", 5*time.Millisecond)
+	ReportCsvWebhookConfigReload()
+
+	count, err := testutil.GatherAndCount(defaultRegistry, "odf_csv_webhook_admissions_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = testutil.GatherAndCount(defaultRegistry, "odf_csv_webhook_admission_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(csvWebhookConfigReloads))
+
+	problems, err := testutil.GatherAndLint(defaultRegistry,
+		"odf_csv_webhook_admissions_total", "odf_csv_webhook_admission_duration_seconds", "odf_csv_webhook_config_reloads_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(problems))
+}
+
+func TestReportUXBackendSecretMetrics(t *testing.T) {
+	expiry := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	ReportUXBackendSecretExpiry("ux-cert-secret", expiry)
+	ReportUXBackendSecretExpiry("ux-backend-proxy", expiry)
+	ReportUXBackendSecretRotation("ux-backend-proxy")
+
+	count, err := testutil.GatherAndCount(defaultRegistry, "odf_ux_backend_secret_expiry_timestamp_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	assert.Equal(t, float64(expiry.Unix()), testutil.ToFloat64(uxBackendSecretExpiry.WithLabelValues("ux-cert-secret")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(uxBackendSecretRotations.WithLabelValues("ux-backend-proxy")))
+
+	problems, err := testutil.GatherAndLint(defaultRegistry,
+		"odf_ux_backend_secret_expiry_timestamp_seconds", "odf_ux_backend_secret_rotations_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(problems))
+}
@@ -17,6 +17,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/red-hat-storage/odf-operator/controllers"
 )
 
 var (
@@ -38,6 +40,8 @@ type DeployManager struct {
 
 // NewDeployManager creates a DeployManager struct with default configuration
 func NewDeployManager() (*DeployManager, error) {
+	controllers.LoadEnv()
+
 	kubeconfig := os.Getenv("KUBECONFIG")
 	if kubeconfig == "" {
 		return nil, fmt.Errorf("no KUBECONFIG environment variable set")
@@ -82,3 +82,37 @@ func IsOCPUpgradeIncomplete(ctx context.Context, cl client.Client) (bool, error)
 
 	return false, nil
 }
+
+// IsROSACluster returns true if the cluster is a ROSA cluster, which is tagged by its AWS resource tags
+func IsROSACluster(ctx context.Context, cl client.Client) (bool, error) {
+	infrastructure := &configv1.Infrastructure{}
+	infrastructure.Name = "cluster"
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(infrastructure), infrastructure); err != nil {
+		return false, err
+	}
+
+	platformStatus := infrastructure.Status.PlatformStatus
+	if platformStatus == nil || platformStatus.AWS == nil {
+		return false, nil
+	}
+
+	for _, tag := range platformStatus.AWS.ResourceTags {
+		if tag.Key == "red-hat-clustertype" && tag.Value == "rosa" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// IsSingleReplicaTopology returns true if the infrastructure of the cluster runs a single replica of its components,
+// e.g. on single node OpenShift
+func IsSingleReplicaTopology(ctx context.Context, cl client.Client) (bool, error) {
+	infrastructure := &configv1.Infrastructure{}
+	infrastructure.Name = "cluster"
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(infrastructure), infrastructure); err != nil {
+		return false, err
+	}
+
+	return infrastructure.Status.InfrastructureTopology == configv1.SingleReplicaTopologyMode, nil
+}
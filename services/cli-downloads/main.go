@@ -0,0 +1,74 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// cli-downloads-server serves the odf CLI binaries copied from the CLI image, so that
+// the disconnected clusters can download the CLI from the console.
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"time"
+)
+
+func main() {
+	port := 8080
+	if value := os.Getenv("CLI_DOWNLOADS_PORT"); value != "" {
+		var err error
+		if port, err = strconv.Atoi(value); err != nil {
+			log.Fatalf("invalid CLI_DOWNLOADS_PORT %q: %v", value, err)
+		}
+	}
+
+	dir := os.Getenv("CLI_DOWNLOADS_DIR")
+	if dir == "" {
+		dir = "/srv/odf-cli"
+	}
+	if _, err := os.Stat(dir); err != nil {
+		log.Fatalf("failed to read the CLI binaries: %v", err)
+	}
+
+	fileServer := http.FileServer(http.Dir(dir))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if name := path.Base(r.URL.Path); name == "odf" || name == "odf.exe" {
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		}
+		fileServer.ServeHTTP(w, r)
+	})
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("serving the CLI binaries of %s on port %d", dir, port)
+	log.Fatal(server.ListenAndServe())
+}
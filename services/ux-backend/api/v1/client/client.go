@@ -0,0 +1,222 @@
+// Package client is a Go client of the v1 API of the ux-backend server
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
+)
+
+// APIError is returned when the server replies with an error
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ux-backend request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Client calls the v1 API of the ux-backend server
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+}
+
+// Option configures the client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for the requests, e.g. to trust the service CA
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBearerToken authenticates the requests with the token, oauth-proxy accepts the tokens of the users and of the
+// service accounts
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// New returns a client of the server at the base URL, e.g. https://ux-backend-proxy.openshift-storage.svc:8888
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ux-backend URL %q: %w", baseURL, err)
+	}
+
+	c := &Client{baseURL: u, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// ExpandStorage adds a device set to a StorageCluster, with a pool and a StorageClass using it, or scales the device
+// set of the device class. The request is validated before the cluster is changed, and nothing is changed for a dry
+// run.
+func (c *Client) ExpandStorage(ctx context.Context, req *apiv1.ExpandStorageRequest) (*apiv1.ExpandStorageResponse, error) {
+	result := &apiv1.ExpandStorageResponse{}
+	if _, err := c.do(ctx, http.MethodPost, apiv1.ExpandStoragePath, nil, req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PreviewExpandStorage returns the capacity and the placement of the OSDs projected for an expandstorage request,
+// the cluster is not changed
+func (c *Client) PreviewExpandStorage(ctx context.Context, req *apiv1.ExpandStorageRequest) (*apiv1.ExpandStoragePreviewResponse, error) {
+	result := &apiv1.ExpandStoragePreviewResponse{}
+	if _, err := c.do(ctx, http.MethodPost, apiv1.ExpandStoragePreviewPath, nil, req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreatePeerToken returns a token to onboard a peer cluster
+func (c *Client) CreatePeerToken(ctx context.Context) (string, error) {
+	var token string
+	_, err := c.do(ctx, http.MethodPost, apiv1.PeerTokensPath, nil, nil, &token)
+	return token, err
+}
+
+// GetFeatureFlags returns the value of the flags. An APIError is returned with the flags when all of them failed.
+func (c *Client) GetFeatureFlags(ctx context.Context, flags ...string) (apiv1.FeatureFlagsResponse, error) {
+	result := apiv1.FeatureFlagsResponse{}
+	status, err := c.do(ctx, http.MethodGet, apiv1.FeatureFlagsPath, url.Values{"flags": {strings.Join(flags, ",")}}, nil, &result)
+	if status == http.StatusInternalServerError && len(result) > 0 {
+		return result, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetBucket returns how the bucket was created
+func (c *Client) GetBucket(ctx context.Context, name string) (*apiv1.BucketResponse, error) {
+	result := &apiv1.BucketResponse{}
+	if _, err := c.do(ctx, http.MethodGet, apiv1.BucketPath+url.PathEscape(name), nil, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetStorages returns the StorageClusters of each namespace
+func (c *Client) GetStorages(ctx context.Context) (*apiv1.StoragesResponse, error) {
+	result := &apiv1.StoragesResponse{}
+	if _, err := c.do(ctx, http.MethodGet, apiv1.StoragesPath, nil, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StartDeviceDiscovery starts the discovery of the devices on the selected nodes, or updates the selected nodes
+func (c *Client) StartDeviceDiscovery(ctx context.Context, req *apiv1.DiscoveryRequest) error {
+	_, err := c.do(ctx, http.MethodPut, apiv1.DeviceFinderPath, nil, req, nil)
+	return err
+}
+
+// GetDiscoveredDevices returns the devices discovered on each node
+func (c *Client) GetDiscoveredDevices(ctx context.Context) (*apiv1.DiscoveryResponse, error) {
+	result := &apiv1.DiscoveryResponse{}
+	if _, err := c.do(ctx, http.MethodGet, apiv1.DeviceFinderPath, nil, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CheckRegistry checks the access to a repository of a registry. A registry which cannot be accessed is reported in
+// the response, not as an error.
+func (c *Client) CheckRegistry(ctx context.Context, req *apiv1.RegistryCheckRequest) (*apiv1.RegistryCheckResponse, error) {
+	result := &apiv1.RegistryCheckResponse{}
+	status, err := c.do(ctx, http.MethodPost, apiv1.RegistryChecksPath, nil, req, result)
+	if status == http.StatusBadRequest && result.Message != "" {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetOpenAPIDocument returns the OpenAPI document served by the server
+func (c *Client) GetOpenAPIDocument(ctx context.Context) (string, error) {
+	var document string
+	_, err := c.do(ctx, http.MethodGet, apiv1.OpenAPIPath, nil, nil, &document)
+	return document, err
+}
+
+// do sends the request and decodes the response into out, a *string receives the body as is. The body of an error
+// response is also decoded into out, so that the operations returning results with their errors can read them.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) (int, error) {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode the request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return 0, err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read the response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		errorResponse := &apiv1.ErrorResponse{}
+		if json.Unmarshal(data, errorResponse) == nil && errorResponse.Error.Message != "" {
+			return resp.StatusCode, &APIError{StatusCode: resp.StatusCode, Message: errorResponse.Error.Message}
+		}
+		if out != nil {
+			_ = json.Unmarshal(data, out)
+		}
+		return resp.StatusCode, &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+
+	switch out := out.(type) {
+	case nil:
+	case *string:
+		*out = string(data)
+	default:
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode the response: %w", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
+	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
+)
+
+func newTestServer(t *testing.T) *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc(apiv1.StoragesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			util.HTTPError(w, r, "no token", http.StatusUnauthorized)
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(apiv1.StoragesResponse{OperatorNamespace: "openshift-storage"}))
+	})
+	mux.HandleFunc(apiv1.FeatureFlagsPath, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "noobaa,rgw", r.URL.Query().Get("flags"))
+		w.WriteHeader(http.StatusInternalServerError)
+		assert.NoError(t, json.NewEncoder(w).Encode(apiv1.FeatureFlagsResponse{
+			"noobaa": {Error: "failed"}, "rgw": {Error: "failed"}}))
+	})
+	mux.HandleFunc(apiv1.PeerTokensPath, func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("peer-token"))
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc(apiv1.ExpandStoragePath, func(w http.ResponseWriter, r *http.Request) {
+		util.HTTPErrorUnsupportedMethod(w, r, http.MethodPost)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, WithHTTPClient(server.Client()), WithBearerToken("token"))
+	assert.NoError(t, err)
+	return c
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t)
+
+	storages, err := c.GetStorages(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "openshift-storage", storages.OperatorNamespace)
+
+	token, err := c.CreatePeerToken(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "peer-token", token)
+
+	// the flags are returned with the error when all of them failed
+	flags, err := c.GetFeatureFlags(ctx, "noobaa", "rgw")
+	assert.Error(t, err)
+	assert.Equal(t, "failed", flags["rgw"].Error)
+
+	// the errors are decoded from the error envelope
+	_, err = c.GetDiscoveredDevices(ctx)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	err = c.StartDeviceDiscovery(ctx, &apiv1.DiscoveryRequest{})
+	assert.True(t, errors.As(err, &apiErr))
+
+	_, err = c.ExpandStorage(ctx, &apiv1.ExpandStorageRequest{})
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusMethodNotAllowed, apiErr.StatusCode)
+	assert.Equal(t, "Unsupported method: POST", strings.TrimSpace(apiErr.Message))
+}
+
+// TestOpenAPIDocument checks that the paths of the API are described by the OpenAPI document
+func TestOpenAPIDocument(t *testing.T) {
+	document := struct {
+		Paths map[string]any `json:"paths"`
+	}{}
+	assert.NoError(t, yaml.Unmarshal(apiv1.OpenAPIDocument, &document))
+
+	for _, path := range []string{apiv1.ExpandStoragePath, apiv1.PeerTokensPath, apiv1.FeatureFlagsPath,
+		apiv1.BucketPath + "{name}", apiv1.StoragesPath, apiv1.DeviceFinderPath, apiv1.RegistryChecksPath, apiv1.OpenAPIPath} {
+		assert.Contains(t, document.Paths, strings.TrimPrefix(path, apiv1.PathPrefix), path)
+	}
+}
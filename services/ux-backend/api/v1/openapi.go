@@ -0,0 +1,10 @@
+package v1
+
+import (
+	_ "embed"
+)
+
+// OpenAPIDocument is the OpenAPI document of the v1 API
+//
+//go:embed openapi.yaml
+var OpenAPIDocument []byte
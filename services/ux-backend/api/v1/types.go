@@ -0,0 +1,227 @@
+// Package v1 contains the types of the v1 API of the ux-backend server. The API is described by the OpenAPI
+// document openapi.yaml, which is served by the server at OpenAPIPath.
+package v1
+
+import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// PathPrefix is the prefix of the paths of the v1 API
+	PathPrefix = "/v1"
+
+	ExpandStoragePath        = PathPrefix + "/expandstorage"
+	ExpandStoragePreviewPath = PathPrefix + "/expandstorage/preview"
+	PeerTokensPath           = PathPrefix + "/onboarding/peer-tokens"
+	FeatureFlagsPath         = PathPrefix + "/info/featureflags"
+	BucketPath               = PathPrefix + "/info/bucket/"
+	StoragesPath             = PathPrefix + "/info/storages"
+	DeviceFinderPath         = PathPrefix + "/cnsa/devicefinder"
+	RegistryChecksPath       = PathPrefix + "/cnsa/registry-checks"
+	OpenAPIPath              = PathPrefix + "/openapi.yaml"
+)
+
+// ErrorResponse is the body of all the error responses of the API
+type ErrorResponse struct {
+	Error ErrorDetails `json:"error"`
+}
+
+// ErrorDetails describes the error of a request
+type ErrorDetails struct {
+	// Code is the HTTP status code of the response
+	Code int `json:"code"`
+	// Message is the human readable description of the error
+	Message string `json:"message"`
+}
+
+// ExpandStorageRequest adds a device set to a StorageCluster, with a pool and a StorageClass using it. When the device
+// class is already used, the device set with the same attributes is scaled instead, or a device set is added without
+// a pool and a StorageClass.
+type ExpandStorageRequest struct {
+	StorageClassForOSDs string              `json:"storageClassForOSDs"`
+	DeviceClass         string              `json:"deviceClass"`
+	EnableEncryption    bool                `json:"enableEncryption"`
+	Storage             string              `json:"storage"`
+	Replica             int                 `json:"replica"`
+	Count               int                 `json:"count"`
+	StorageClusterName  string              `json:"storageClusterName"`
+	PoolDetails         PoolDetails         `json:"poolDetails"`
+	StorageClassDetails StorageClassDetails `json:"storageClassDetails"`
+	// DeviceType is the type of the devices, either "SSD", "HDD" or "NVMe", "SSD" is the default
+	DeviceType string `json:"deviceType,omitempty"`
+	// Portable OSDs can move to another node with their PV, it requires PVs which can be attached to other nodes
+	Portable bool `json:"portable,omitempty"`
+	// Placement and PreparePlacement override the placement of the OSDs and of the jobs preparing their devices
+	Placement        *cephv1.Placement `json:"placement,omitempty"`
+	PreparePlacement *cephv1.Placement `json:"preparePlacement,omitempty"`
+	// Resources override the resources of the OSDs
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// MetadataDevice stores the metadata of each OSD on a separate device, e.g. an NVMe device for HDD OSDs
+	MetadataDevice *DeviceDetails `json:"metadataDevice,omitempty"`
+	// WalDevice stores the write-ahead log of each OSD on a separate device
+	WalDevice *DeviceDetails `json:"walDevice,omitempty"`
+	// DryRun validates the request and returns the objects without changing the cluster
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+const (
+	// DeviceSetCreated is the action of an expandstorage request which added a device set
+	DeviceSetCreated = "created"
+	// DeviceSetScaled is the action of an expandstorage request which increased the count of an existing device set
+	DeviceSetScaled = "scaled"
+)
+
+// DeviceDetails is a device of each OSD besides its data device
+type DeviceDetails struct {
+	StorageClassName string `json:"storageClassName"`
+	// Storage is the size of the device, as a Kubernetes quantity
+	Storage string `json:"storage"`
+}
+
+// ExpandStorageResponse contains the objects created or updated by an expandstorage request
+type ExpandStorageResponse struct {
+	DryRun bool `json:"dryRun"`
+	// Action is either DeviceSetCreated or DeviceSetScaled
+	Action        string `json:"action"`
+	DeviceSetName string `json:"deviceSetName"`
+	// Objects are the updated StorageCluster and, for a new device class, the created CephBlockPools for the block
+	// volume type and the created StorageClass
+	Objects []*unstructured.Unstructured `json:"objects"`
+	// Warnings describe the parts of the request which are ignored, e.g. the pool and StorageClass details for a
+	// device class which is already used
+	Warnings []string `json:"warnings"`
+}
+
+// ExpandStoragePreviewResponse is the projected result of an expandstorage request, the cluster is not changed
+type ExpandStoragePreviewResponse struct {
+	// Action is either DeviceSetCreated or DeviceSetScaled
+	Action        string `json:"action"`
+	DeviceSetName string `json:"deviceSetName"`
+	// NewOSDs is the number of OSDs added by the request
+	NewOSDs int `json:"newOSDs"`
+	// RawCapacity is the capacity of the OSDs of the device class after the expansion
+	RawCapacity      resource.Quantity `json:"rawCapacity"`
+	AddedRawCapacity resource.Quantity `json:"addedRawCapacity"`
+	// UsableCapacity is the capacity of the data stored in the pool after the expansion, after replication or erasure
+	// coding, and limited by the placement of the OSDs
+	UsableCapacity      resource.Quantity `json:"usableCapacity"`
+	AddedUsableCapacity resource.Quantity `json:"addedUsableCapacity"`
+	FailureDomain       string            `json:"failureDomain"`
+	// Placement is the number of OSDs of the device class in each failure domain, it is empty when the failure domains
+	// of the storage nodes are unknown
+	Placement []FailureDomainPlacement `json:"placement"`
+	// Warnings describe the problems of the expansion, including the reasons the request would be rejected
+	Warnings []string `json:"warnings"`
+}
+
+// FailureDomainPlacement is the placement of the OSDs of a device class in a failure domain
+type FailureDomainPlacement struct {
+	Name        string `json:"name"`
+	CurrentOSDs int    `json:"currentOSDs"`
+	NewOSDs     int    `json:"newOSDs"`
+	// AvailableDevices is the number of devices available for new OSDs, it is omitted when the PVs of the OSDs are
+	// provisioned dynamically
+	AvailableDevices *int `json:"availableDevices,omitempty"`
+}
+
+// PoolDetails is the pool created on the new device class
+type PoolDetails struct {
+	// VolumeType is either "block" for a CephBlockPool, or "filesystem" for a data pool of a CephFilesystem
+	VolumeType           string `json:"volumeType"`
+	PoolName             string `json:"poolName"`
+	DataProtectionPolicy int    `json:"dataProtectionPolicy"`
+	EnableCompression    bool   `json:"enableCompression"`
+	FilesystemName       string `json:"filesystemName"`
+	FailureDomain        string `json:"failureDomain"`
+	// ErasureCoded creates an erasure coded pool instead of a replicated one, the dataProtectionPolicy is then the
+	// size of the replicated metadata pool of a block pool
+	ErasureCoded *ErasureCodedDetails `json:"erasureCoded,omitempty"`
+}
+
+// ErasureCodedDetails is the erasure coding profile of a pool. Every object is split into data chunks, and coding
+// chunks are added, each chunk is stored in a different failure domain.
+type ErasureCodedDetails struct {
+	DataChunks   uint `json:"dataChunks"`
+	CodingChunks uint `json:"codingChunks"`
+	// Algorithm is either "isa" or "jerasure", the Ceph default is used if empty
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// StorageClassDetails is the StorageClass created for the new pool
+type StorageClassDetails struct {
+	ReclaimPolicy                string `json:"reclaimPolicy"`
+	Name                         string `json:"name"`
+	VolumeBindingMode            string `json:"volumeBindingMode"`
+	EnableStorageClassEncryption bool   `json:"enableStorageClassEncryption"`
+	EncryptionKMSID              string `json:"encryptionKMSID"`
+}
+
+// FeatureFlagsResponse contains the result of each of the requested flags
+type FeatureFlagsResponse map[string]FeatureFlag
+
+// FeatureFlag is the value of a flag, or the error which occurred when checking it
+type FeatureFlag struct {
+	Value bool   `json:"value"`
+	Error string `json:"error,omitempty"`
+}
+
+// BucketResponse describes how a bucket was created, either "obc" or "s3"
+type BucketResponse struct {
+	CreatedVia string `json:"createdVia"`
+}
+
+// StoragesResponse describes the storage of each namespace with a StorageCluster
+type StoragesResponse struct {
+	OperatorNamespace string                             `json:"operatorNamespace"`
+	ClusterNamespaces map[string]ClusterNamespaceStorage `json:"clusterNamespaces"`
+}
+
+// ClusterNamespaceStorage describes the StorageCluster of a namespace
+type ClusterNamespaceStorage struct {
+	StorageClusterName string `json:"storageClusterName"`
+	// DeploymentType is either "internal" or "external"
+	DeploymentType    string `json:"deploymentType"`
+	RgwSecureEndpoint string `json:"rgwSecureEndpoint,omitempty"`
+}
+
+// DiscoveryRequest represents the request body for device discovery
+type DiscoveryRequest struct {
+	NodeSelector *corev1.NodeSelector `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration  `json:"tolerations,omitempty"`
+}
+
+// DiscoveryResponse contains the discovered devices of each node
+type DiscoveryResponse struct {
+	Devices map[string]DiscoveryResult `json:"devices,omitempty"`
+}
+
+// DiscoveryResult represents the result of device discovery for a node
+type DiscoveryResult struct {
+	DiscoveredDevices []DiscoveredDevice `json:"discoveredDevices"`
+}
+
+// DiscoveredDevice shows the list of discovered devices with their properties
+type DiscoveredDevice struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	WWN  string `json:"WWN"`
+}
+
+// RegistryCheckRequest checks the access to a repository of a registry with a pull secret
+type RegistryCheckRequest struct {
+	RegistryURL            string `json:"registryURL"`
+	RegistryRepositoryName string `json:"registryRepositoryName"`
+	// SecretKey is the name of the pull secret
+	SecretKey       string `json:"secretKey"`
+	SecretNamespace string `json:"secretNamespace"`
+}
+
+// RegistryCheckResponse is the result of a registry check
+type RegistryCheckResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
@@ -4,10 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"html"
 	"net/http"
 
-	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
 
 	nbv1a1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
@@ -34,19 +33,19 @@ func handleGet(w http.ResponseWriter, r *http.Request, client ctrlclient.Client)
 	bucketName := r.PathValue("name")
 	if bucketName == "" {
 		klog.Errorf("bucket name is required in path")
-		http.Error(w, "bucket name is required in path", http.StatusBadRequest)
+		util.HTTPError(w, r, "bucket name is required in path", http.StatusBadRequest)
 		return
 	}
 
 	creationMethod, err := getBucketCreationMethod(r.Context(), client, bucketName)
 	if err != nil {
 		klog.Errorf("failed to get bucket creation method: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to get bucket creation method: %v", err), http.StatusInternalServerError)
+		util.HTTPError(w, r, fmt.Sprintf("Failed to get bucket creation method: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	result := map[string]string{
-		"createdVia": creationMethod,
+	result := apiv1.BucketResponse{
+		CreatedVia: creationMethod,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -54,7 +53,7 @@ func handleGet(w http.ResponseWriter, r *http.Request, client ctrlclient.Client)
 
 	if err := json.NewEncoder(w).Encode(result); err != nil {
 		klog.Errorf("failed to encode response: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		util.HTTPError(w, r, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 }
@@ -81,12 +80,5 @@ func getBucketCreationMethod(ctx context.Context, client ctrlclient.Client, buck
 }
 
 func handleUnsupportedMethod(w http.ResponseWriter, r *http.Request) {
-	klog.Infof("Only GET method should be used for this endpoint %s", r.URL.Path)
-	w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
-	w.Header().Set("Allow", "GET")
-	w.WriteHeader(http.StatusMethodNotAllowed)
-
-	if _, err := fmt.Fprintf(w, "Unsupported method: %s", html.EscapeString(r.Method)); err != nil {
-		klog.Errorf("failed to write data to response writer: %v", err)
-	}
+	util.HTTPErrorUnsupportedMethod(w, r, "GET")
 }
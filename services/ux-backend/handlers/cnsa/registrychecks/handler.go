@@ -3,27 +3,15 @@ package registrychecks
 import (
 	"encoding/json"
 	"fmt"
-	"html"
 	"net/http"
 
-	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type RegistryCheckRequest struct {
-	RegistryURL            string `json:"registryURL"`
-	RegistryRepositoryName string `json:"registryRepositoryName"`
-	SecretKey              string `json:"secretKey"`
-	SecretNamespace        string `json:"secretNamespace"`
-}
-
-type RegistryCheckResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-}
-
 func HandleMessage(w http.ResponseWriter, r *http.Request, client client.Client) {
 	switch r.Method {
 	case "POST":
@@ -34,10 +22,10 @@ func HandleMessage(w http.ResponseWriter, r *http.Request, client client.Client)
 }
 
 func handleInput(w http.ResponseWriter, r *http.Request, client client.Client) {
-	var req RegistryCheckRequest
+	var req apiv1.RegistryCheckRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		klog.Errorf("failed to decode request body: %v", err)
-		response := RegistryCheckResponse{
+		response := apiv1.RegistryCheckResponse{
 			Success: false,
 			Message: "Invalid request body",
 		}
@@ -45,15 +33,20 @@ func handleInput(w http.ResponseWriter, r *http.Request, client client.Client) {
 		w.WriteHeader(http.StatusBadRequest)
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			klog.Errorf("failed to encode response: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			util.HTTPError(w, r, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 		return
 	}
+	// the pull secret is read on behalf of the user
+	if !util.AuthorizeRequest(w, r, client, authorizationv1.ResourceAttributes{
+		Resource: "secrets", Namespace: req.SecretNamespace, Name: req.SecretKey, Verb: "get"}) {
+		return
+	}
 	err := util.TestRegistryConnection(r.Context(), req.RegistryURL, req.RegistryRepositoryName, req.SecretKey, req.SecretNamespace, client)
 	if err != nil {
 		klog.Errorf("failed to test registry connection: %v", err)
-		response := RegistryCheckResponse{
+		response := apiv1.RegistryCheckResponse{
 			Success: false,
 			Message: fmt.Sprintf("Registry connection failed: %v", err),
 		}
@@ -61,12 +54,12 @@ func handleInput(w http.ResponseWriter, r *http.Request, client client.Client) {
 		w.WriteHeader(http.StatusBadRequest)
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			klog.Errorf("failed to encode response: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			util.HTTPError(w, r, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 		return
 	}
-	response := RegistryCheckResponse{
+	response := apiv1.RegistryCheckResponse{
 		Success: true,
 		Message: "Registry connection successful",
 	}
@@ -74,18 +67,11 @@ func handleInput(w http.ResponseWriter, r *http.Request, client client.Client) {
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		klog.Errorf("failed to encode response: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		util.HTTPError(w, r, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 }
 
 func handleUnsupportedMethod(w http.ResponseWriter, r *http.Request) {
-	klog.Infof("Only POST method should be used to send data to this endpoint %s", r.URL.Path)
-	w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
-	w.Header().Set("Allow", "POST")
-	w.WriteHeader(http.StatusMethodNotAllowed)
-
-	if _, err := fmt.Fprintf(w, "Unsupported method : %s", html.EscapeString(r.Method)); err != nil {
-		klog.Errorf("failed write data to response writer: %v", err)
-	}
+	util.HTTPErrorUnsupportedMethod(w, r, "POST")
 }
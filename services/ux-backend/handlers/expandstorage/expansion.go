@@ -0,0 +1,623 @@
+package expandstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	ocsv1 "github.com/red-hat-storage/ocs-operator/api/v4/v1"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
+	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	volumeTypeBlock      = "block"
+	volumeTypeFilesystem = "filesystem"
+
+	// metadataPoolSuffix is the suffix of the replicated metadata pool of an erasure coded block pool
+	metadataPoolSuffix = "-metadata"
+
+	// noProvisioner is the provisioner of the StorageClasses of local volumes, their PVs are created up front
+	noProvisioner = "kubernetes.io/no-provisioner"
+)
+
+// failureDomains are the CRUSH bucket types a pool can use as its failure domain
+var failureDomains = []string{"osd", "host", "chassis", "rack", "row", "pdu", "pod", "room", "datacenter", "zone", "region"}
+
+// deviceTypes maps the lowercase device types to the values of the StorageDeviceSets
+var deviceTypes = map[string]string{"ssd": "SSD", "hdd": "HDD", "nvme": "NVMe"}
+
+// requestError is an error of the request, which is replied with its status code
+type requestError struct {
+	code    int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...any) error {
+	return &requestError{code: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func conflict(format string, args ...any) error {
+	return &requestError{code: http.StatusConflict, message: fmt.Sprintf(format, args...)}
+}
+
+// expansion is a validated expandstorage request, with all the objects it creates or updates. It is applied step by
+// step, and the steps which were already applied are undone when a later one fails.
+type expansion struct {
+	client    client.Client
+	namespace string
+
+	// storageCluster is the StorageCluster with the new or scaled device set and, for a filesystem, the new data pool
+	storageCluster *ocsv1.StorageCluster
+	deviceSetName  string
+	// scaledBy is the count added to an existing device set, it is 0 when a device set is created
+	scaledBy     int
+	dataPoolName string
+	// cephBlockPools are only created for the block volume type, an erasure coded pool has a replicated metadata pool
+	cephBlockPools []*cephv1.CephBlockPool
+	storageClass   *storagev1.StorageClass
+	// warnings are the parts of the request which are ignored
+	warnings []string
+}
+
+// newExpansion validates the request against the cluster and builds the objects, the cluster is not changed
+func newExpansion(ctx context.Context, cl client.Client, namespace string, req *apiv1.ExpandStorageRequest) (*expansion, error) {
+
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	deviceSet, err := newRequestDeviceSet(req)
+	if err != nil {
+		return nil, err
+	}
+	storageQty := *deviceSet.DataPVCTemplate.Spec.Resources.Requests.Storage()
+
+	dataProtectionPolicy, err := util.SafeIntToUint(req.PoolDetails.DataProtectionPolicy)
+	if err != nil {
+		return nil, badRequest("invalid dataProtectionPolicy: %v", err)
+	}
+
+	var reclaimPolicy *corev1.PersistentVolumeReclaimPolicy
+	switch policy := corev1.PersistentVolumeReclaimPolicy(req.StorageClassDetails.ReclaimPolicy); policy {
+	case "":
+	case corev1.PersistentVolumeReclaimDelete, corev1.PersistentVolumeReclaimRetain:
+		reclaimPolicy = &policy
+	default:
+		return nil, badRequest("invalid reclaimPolicy %q", policy)
+	}
+
+	var volumeBindingMode *storagev1.VolumeBindingMode
+	switch mode := storagev1.VolumeBindingMode(req.StorageClassDetails.VolumeBindingMode); mode {
+	case "":
+	case storagev1.VolumeBindingImmediate, storagev1.VolumeBindingWaitForFirstConsumer:
+		volumeBindingMode = &mode
+	default:
+		return nil, badRequest("invalid volumeBindingMode %q", mode)
+	}
+
+	storageCluster, err := getStorageCluster(ctx, cl, namespace, req.StorageClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	osdStorageClass := &storagev1.StorageClass{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: req.StorageClassForOSDs}, osdStorageClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, badRequest("storageClass %q for the OSDs not found", req.StorageClassForOSDs)
+		}
+		return nil, fmt.Errorf("failed to get storageClass: %w", err)
+	}
+
+	if req.Portable && osdStorageClass.Provisioner == noProvisioner {
+		return nil, badRequest("portable OSDs require PVs which can be attached to other nodes, storageClass %q provides local volumes", osdStorageClass.Name)
+	}
+
+	if err := validateAvailableVolumes(ctx, cl, osdStorageClass, storageQty, req.Count*req.Replica); err != nil {
+		return nil, err
+	}
+
+	for _, device := range []struct {
+		name     string
+		template *corev1.PersistentVolumeClaim
+	}{
+		{"metadataDevice", deviceSet.MetadataPVCTemplate},
+		{"walDevice", deviceSet.WalPVCTemplate},
+	} {
+		if device.template == nil {
+			continue
+		}
+		storageClass := &storagev1.StorageClass{}
+		if err := cl.Get(ctx, client.ObjectKey{Name: *device.template.Spec.StorageClassName}, storageClass); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, badRequest("storageClass %q of the %s not found", *device.template.Spec.StorageClassName, device.name)
+			}
+			return nil, fmt.Errorf("failed to get storageClass: %w", err)
+		}
+		if err := validateAvailableVolumes(ctx, cl, storageClass, *device.template.Spec.Resources.Requests.Storage(), req.Count*req.Replica); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &expansion{
+		client:         cl,
+		namespace:      namespace,
+		storageCluster: storageCluster,
+	}
+
+	// a device class already used by a device set gets more capacity, the pools using it are not changed
+	deviceSets := storageCluster.Spec.StorageDeviceSets
+	scaled, deviceClassUsed := findDeviceSet(deviceSets, &deviceSet)
+	if scaled != -1 {
+		e.deviceSetName = deviceSets[scaled].Name
+		e.scaledBy = req.Count
+		deviceSets[scaled].Count += req.Count
+		e.warnings = ignoredDetailsWarnings(req)
+		return e, nil
+	}
+
+	deviceSet.Name = newDeviceSetName(deviceSets, req.DeviceClass)
+	e.deviceSetName = deviceSet.Name
+	storageCluster.Spec.StorageDeviceSets = append(deviceSets, deviceSet)
+	if deviceClassUsed {
+		e.warnings = ignoredDetailsWarnings(req)
+		return e, nil
+	}
+
+	if err := validatePoolRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := cl.Get(ctx, client.ObjectKey{Name: req.StorageClassDetails.Name}, &storagev1.StorageClass{}); err == nil {
+		return nil, conflict("storageClass %q already exists", req.StorageClassDetails.Name)
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get storageClass: %w", err)
+	}
+
+	if req.PoolDetails.ErasureCoded != nil {
+		if err := validateErasureCoding(req, storageCluster); err != nil {
+			return nil, err
+		}
+	}
+
+	pool := req.PoolDetails
+	poolSpec := newPoolSpec(req.DeviceClass, dataProtectionPolicy, pool.ErasureCoded, pool.EnableCompression, pool.FailureDomain)
+	switch pool.VolumeType {
+	case volumeTypeBlock:
+		poolName, dataPoolName := pool.PoolName, ""
+		if pool.ErasureCoded != nil {
+			// RBD keeps the images in a replicated pool, only their data can be stored in an erasure coded pool
+			poolName, dataPoolName = pool.PoolName+metadataPoolSuffix, pool.PoolName
+			metadataPoolSpec := newPoolSpec(req.DeviceClass, dataProtectionPolicy, nil, false, pool.FailureDomain)
+			e.cephBlockPools = append(e.cephBlockPools, newCephBlockPool(poolName, namespace, metadataPoolSpec, storageCluster.Spec.Arbiter.Enable))
+		}
+		e.cephBlockPools = append(e.cephBlockPools, newCephBlockPool(pool.PoolName, namespace, poolSpec, storageCluster.Spec.Arbiter.Enable))
+
+		for _, cephBlockPool := range e.cephBlockPools {
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(cephBlockPool), &cephv1.CephBlockPool{}); err == nil {
+				return nil, conflict("cephBlockPool %q already exists", cephBlockPool.Name)
+			} else if !k8serrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get cephBlockPool: %w", err)
+			}
+		}
+
+		e.storageClass = newCephBlockPoolStorageClass(req.StorageClassDetails.Name, poolName, dataPoolName, reclaimPolicy, volumeBindingMode, req.StorageClassDetails.EnableStorageClassEncryption, req.StorageClassDetails.EncryptionKMSID)
+
+	case volumeTypeFilesystem:
+		filesystems := &storageCluster.Spec.ManagedResources.CephFilesystems
+		if slices.ContainsFunc(filesystems.AdditionalDataPools, func(dataPool cephv1.NamedPoolSpec) bool { return dataPool.Name == pool.PoolName }) {
+			return nil, conflict("data pool %q already exists", pool.PoolName)
+		}
+
+		e.dataPoolName = pool.PoolName
+		filesystems.AdditionalDataPools = append(filesystems.AdditionalDataPools, newCephFilesystemDataPool(pool.PoolName, poolSpec))
+		e.storageClass = newCephFilesystemStorageClass(req.StorageClassDetails.Name, pool.PoolName, reclaimPolicy, volumeBindingMode, pool.FilesystemName)
+	}
+
+	return e, nil
+}
+
+// ignoredDetailsWarnings returns the warnings for the pool and StorageClass details of a request for a device class
+// which is already used, no pool or StorageClass is created for it
+func ignoredDetailsWarnings(req *apiv1.ExpandStorageRequest) []string {
+	var warnings []string
+	if req.PoolDetails != (apiv1.PoolDetails{}) {
+		warnings = append(warnings, fmt.Sprintf("device class %q is already used, poolDetails are ignored and no pool is created", req.DeviceClass))
+	}
+	if req.StorageClassDetails != (apiv1.StorageClassDetails{}) {
+		warnings = append(warnings, fmt.Sprintf("device class %q is already used, storageClassDetails are ignored and no StorageClass is created", req.DeviceClass))
+	}
+	return warnings
+}
+
+func getStorageCluster(ctx context.Context, cl client.Client, namespace, name string) (*ocsv1.StorageCluster, error) {
+	storageCluster := &ocsv1.StorageCluster{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, storageCluster); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, badRequest("storageCluster %q not found in namespace %q", name, namespace)
+		}
+		return nil, fmt.Errorf("failed to get storageCluster: %w", err)
+	}
+	return storageCluster, nil
+}
+
+// validateRequest validates the fields of the device set which do not depend on the cluster
+func validateRequest(req *apiv1.ExpandStorageRequest) error {
+
+	for _, field := range []struct{ name, value string }{
+		{"storageClusterName", req.StorageClusterName},
+		{"storageClassForOSDs", req.StorageClassForOSDs},
+		{"deviceClass", req.DeviceClass},
+		{"storage", req.Storage},
+	} {
+		if field.value == "" {
+			return badRequest("%s is required", field.name)
+		}
+	}
+
+	if req.Count <= 0 || req.Replica <= 0 {
+		return badRequest("count and replica must be positive")
+	}
+
+	if _, ok := deviceTypes[strings.ToLower(req.DeviceType)]; req.DeviceType != "" && !ok {
+		return badRequest("invalid deviceType %q, valid values are SSD, HDD and NVMe", req.DeviceType)
+	}
+
+	if req.Resources != nil {
+		for name, limit := range req.Resources.Limits {
+			if request, ok := req.Resources.Requests[name]; ok && request.Cmp(limit) > 0 {
+				return badRequest("the %s request %s of the OSDs exceeds their limit %s", name, request.String(), limit.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// newRequestDeviceSet builds the device set of the request, with its optional metadata and WAL devices
+func newRequestDeviceSet(req *apiv1.ExpandStorageRequest) (ocsv1.StorageDeviceSet, error) {
+
+	storageQty, err := resource.ParseQuantity(req.Storage)
+	if err != nil || storageQty.Sign() <= 0 {
+		return ocsv1.StorageDeviceSet{}, badRequest("invalid storage %q, a positive quantity is required", req.Storage)
+	}
+
+	deviceSet := newStorageDeviceSet(req.StorageClassForOSDs, req.DeviceClass, req.EnableEncryption, storageQty, req.Count, req.Replica)
+	if req.DeviceType != "" {
+		deviceSet.DeviceType = deviceTypes[strings.ToLower(req.DeviceType)]
+	}
+	// rook tunes the OSDs of spinning disks for their latency
+	deviceSet.Config.TuneSlowDeviceClass = deviceSet.DeviceType == "HDD"
+	deviceSet.Portable = req.Portable
+	if req.Placement != nil {
+		deviceSet.Placement = *req.Placement
+	}
+	if req.PreparePlacement != nil {
+		deviceSet.PreparePlacement = *req.PreparePlacement
+	}
+	if req.Resources != nil {
+		deviceSet.Resources = *req.Resources
+	}
+
+	for _, device := range []struct {
+		name     string
+		details  *apiv1.DeviceDetails
+		template **corev1.PersistentVolumeClaim
+	}{
+		{"metadataDevice", req.MetadataDevice, &deviceSet.MetadataPVCTemplate},
+		{"walDevice", req.WalDevice, &deviceSet.WalPVCTemplate},
+	} {
+		if device.details == nil {
+			continue
+		}
+		if device.details.StorageClassName == "" {
+			return ocsv1.StorageDeviceSet{}, badRequest("%s.storageClassName is required", device.name)
+		}
+		qty, err := resource.ParseQuantity(device.details.Storage)
+		if err != nil || qty.Sign() <= 0 {
+			return ocsv1.StorageDeviceSet{}, badRequest("invalid %s.storage %q, a positive quantity is required", device.name, device.details.Storage)
+		}
+		*device.template = ptr.To(newDevicePVCTemplate(device.details.StorageClassName, qty))
+	}
+
+	return deviceSet, nil
+}
+
+// validatePoolRequest validates the fields of the pool and of the StorageClass, they are only created for a new
+// device class
+func validatePoolRequest(req *apiv1.ExpandStorageRequest) error {
+
+	for _, field := range []struct{ name, value string }{
+		{"poolDetails.poolName", req.PoolDetails.PoolName},
+		{"storageClassDetails.name", req.StorageClassDetails.Name},
+	} {
+		if field.value == "" {
+			return badRequest("%s is required", field.name)
+		}
+	}
+
+	switch req.PoolDetails.VolumeType {
+	case volumeTypeBlock:
+	case volumeTypeFilesystem:
+		if req.PoolDetails.FilesystemName == "" {
+			return badRequest("poolDetails.filesystemName is required for the filesystem volume type")
+		}
+	default:
+		return badRequest("invalid volumeType %q", req.PoolDetails.VolumeType)
+	}
+
+	if req.PoolDetails.FailureDomain != "" && !slices.Contains(failureDomains, req.PoolDetails.FailureDomain) {
+		return badRequest("invalid failureDomain %q, valid values are %v", req.PoolDetails.FailureDomain, failureDomains)
+	}
+
+	if req.StorageClassDetails.EnableStorageClassEncryption && req.StorageClassDetails.EncryptionKMSID == "" {
+		return badRequest("storageClassDetails.encryptionKMSID is required when the encryption is enabled")
+	}
+
+	return nil
+}
+
+// validateAvailableVolumes checks that there are enough available PVs for the OSDs of a StorageClass of local volumes.
+// The PVs of the other StorageClasses are provisioned on demand.
+func validateAvailableVolumes(ctx context.Context, cl client.Client, storageClass *storagev1.StorageClass, size resource.Quantity, osds int) error {
+
+	if storageClass.Provisioner != noProvisioner {
+		return nil
+	}
+
+	pvList := &corev1.PersistentVolumeList{}
+	if err := cl.List(ctx, pvList); err != nil {
+		return fmt.Errorf("failed to list persistentVolumes: %w", err)
+	}
+
+	available := 0
+	for i := range pvList.Items {
+		if isAvailableVolume(&pvList.Items[i], storageClass.Name, size) {
+			available++
+		}
+	}
+	if available < osds {
+		return badRequest("%d OSDs require %d available persistentVolumes of storageClass %q with at least %s, %d are available",
+			osds, osds, storageClass.Name, size.String(), available)
+	}
+
+	return nil
+}
+
+// isAvailableVolume returns true if the PV of the StorageClass can be claimed by an OSD of the size
+func isAvailableVolume(pv *corev1.PersistentVolume, storageClassName string, size resource.Quantity) bool {
+	return pv.Spec.StorageClassName == storageClassName && pv.Status.Phase == corev1.VolumeAvailable &&
+		pv.Spec.ClaimRef == nil && pv.Spec.Capacity.Storage().Cmp(size) >= 0
+}
+
+// findDeviceSet returns the index of the device set of the device class with the same attributes, or -1, and whether
+// the device class is used by any device set
+func findDeviceSet(deviceSets []ocsv1.StorageDeviceSet, deviceSet *ocsv1.StorageDeviceSet) (int, bool) {
+	deviceClassUsed := false
+	for i := range deviceSets {
+		if deviceSets[i].DeviceClass != deviceSet.DeviceClass {
+			continue
+		}
+		deviceClassUsed = true
+		if sameDeviceSetAttributes(&deviceSets[i], deviceSet) {
+			return i, true
+		}
+	}
+	return -1, deviceClassUsed
+}
+
+// sameDeviceSetAttributes returns true if the OSDs of both device sets are alike, so that one can be scaled instead
+// of adding the other
+func sameDeviceSetAttributes(a, b *ocsv1.StorageDeviceSet) bool {
+	return a.Replica == b.Replica &&
+		strings.EqualFold(a.DeviceType, b.DeviceType) &&
+		a.Portable == b.Portable &&
+		ptr.Deref(a.Encrypted, false) == ptr.Deref(b.Encrypted, false) &&
+		samePVCTemplate(&a.DataPVCTemplate, &b.DataPVCTemplate) &&
+		samePVCTemplate(a.MetadataPVCTemplate, b.MetadataPVCTemplate) &&
+		samePVCTemplate(a.WalPVCTemplate, b.WalPVCTemplate) &&
+		equality.Semantic.DeepEqual(a.Placement, b.Placement) &&
+		equality.Semantic.DeepEqual(a.PreparePlacement, b.PreparePlacement) &&
+		equality.Semantic.DeepEqual(a.Resources, b.Resources)
+}
+
+// samePVCTemplate returns true if both templates claim PVs of the same StorageClass and size
+func samePVCTemplate(a, b *corev1.PersistentVolumeClaim) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return ptr.Deref(a.Spec.StorageClassName, "") == ptr.Deref(b.Spec.StorageClassName, "") &&
+		a.Spec.Resources.Requests.Storage().Cmp(*b.Spec.Resources.Requests.Storage()) == 0
+}
+
+// newDeviceSetName returns the device class as the name of a new device set, with a suffix if the name is used
+func newDeviceSetName(deviceSets []ocsv1.StorageDeviceSet, deviceClass string) string {
+	name := deviceClass
+	for i := 1; slices.ContainsFunc(deviceSets, func(deviceSet ocsv1.StorageDeviceSet) bool { return deviceSet.Name == name }); i++ {
+		name = fmt.Sprintf("%s-%d", deviceClass, i)
+	}
+	return name
+}
+
+// validateErasureCoding validates the erasure coding profile of the pool. Every chunk of an object is stored in a
+// different failure domain, so there have to be at least as many failure domains as chunks.
+func validateErasureCoding(req *apiv1.ExpandStorageRequest, storageCluster *ocsv1.StorageCluster) error {
+
+	profile := req.PoolDetails.ErasureCoded
+	if profile.DataChunks < 2 || profile.CodingChunks < 1 {
+		return badRequest("an erasure coded pool requires at least 2 data chunks and 1 coding chunk")
+	}
+	if profile.Algorithm != "" && profile.Algorithm != "isa" && profile.Algorithm != "jerasure" {
+		return badRequest("invalid erasure coding algorithm %q", profile.Algorithm)
+	}
+	if storageCluster.Spec.Arbiter.Enable {
+		return badRequest("erasure coded pools are not supported on a stretched storageCluster")
+	}
+
+	failureDomain := req.PoolDetails.FailureDomain
+	if failureDomain == "" {
+		failureDomain = storageCluster.Status.FailureDomain
+	}
+	failureDomainCount, known := countFailureDomains(storageCluster, failureDomain, req.Count*req.Replica)
+	if !known {
+		return badRequest("the number of %q failure domains of storageCluster %q is unknown", failureDomain, storageCluster.Name)
+	}
+
+	if chunks := int(profile.DataChunks + profile.CodingChunks); chunks > failureDomainCount {
+		return badRequest("an erasure coded pool with %d data and %d coding chunks requires %d %q failure domains, the new device set has %d",
+			profile.DataChunks, profile.CodingChunks, chunks, failureDomain, failureDomainCount)
+	}
+
+	return nil
+}
+
+// countFailureDomains returns the number of failure domains the OSDs of the new device set can be spread over
+func countFailureDomains(storageCluster *ocsv1.StorageCluster, failureDomain string, osds int) (int, bool) {
+
+	if failureDomain == "osd" {
+		return osds, true
+	}
+
+	var values []string
+	if failureDomain == storageCluster.Status.FailureDomain && len(storageCluster.Status.FailureDomainValues) > 0 {
+		values = storageCluster.Status.FailureDomainValues
+	} else if storageCluster.Status.NodeTopologies != nil {
+		_, values = storageCluster.Status.NodeTopologies.GetKeyValues(failureDomain)
+	}
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	// the new device set cannot span more failure domains than it has OSDs
+	return min(len(values), osds), true
+}
+
+// apply applies the expansion. When a step fails, the steps which were already applied are undone in reverse order.
+func (e *expansion) apply(ctx context.Context) (err error) {
+
+	var undo []func(context.Context) error
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(undo) - 1; i >= 0; i-- {
+			if undoErr := undo[i](ctx); undoErr != nil {
+				klog.Errorf("failed to undo the expansion of storageCluster %q: %v", e.storageCluster.Name, undoErr)
+				err = errors.Join(err, fmt.Errorf("failed to undo the expansion, the cluster may be left partially changed: %w", undoErr))
+			}
+		}
+	}()
+
+	klog.Infof("Updating storageCluster %q", e.storageCluster.Name)
+	if err := e.client.Update(ctx, e.storageCluster); err != nil {
+		return fmt.Errorf("failed to update storageCluster %q: %w", e.storageCluster.Name, err)
+	}
+	undo = append(undo, e.revertStorageCluster)
+
+	for _, cephBlockPool := range e.cephBlockPools {
+		klog.Infof("Creating cephBlockPool %q", cephBlockPool.Name)
+		if err := e.client.Create(ctx, cephBlockPool); err != nil {
+			return fmt.Errorf("failed to create cephBlockPool %q: %w", cephBlockPool.Name, err)
+		}
+		undo = append(undo, func(ctx context.Context) error {
+			return client.IgnoreNotFound(e.client.Delete(ctx, cephBlockPool))
+		})
+	}
+
+	if e.storageClass == nil {
+		return nil
+	}
+
+	klog.Infof("Creating storageClass %q", e.storageClass.Name)
+	if err := e.client.Create(ctx, e.storageClass); err != nil {
+		return fmt.Errorf("failed to create storageClass %q: %w", e.storageClass.Name, err)
+	}
+
+	return nil
+}
+
+// revertStorageCluster scales the device set back or removes it, and removes the data pool added to the StorageCluster
+func (e *expansion) revertStorageCluster(ctx context.Context) error {
+	klog.Infof("Reverting the expansion of storageCluster %q", e.storageCluster.Name)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		storageCluster := &ocsv1.StorageCluster{}
+		if err := e.client.Get(ctx, client.ObjectKeyFromObject(e.storageCluster), storageCluster); err != nil {
+			return err
+		}
+		if e.scaledBy > 0 {
+			for i := range storageCluster.Spec.StorageDeviceSets {
+				if deviceSet := &storageCluster.Spec.StorageDeviceSets[i]; deviceSet.Name == e.deviceSetName {
+					deviceSet.Count = max(deviceSet.Count-e.scaledBy, 1)
+				}
+			}
+		} else {
+			storageCluster.Spec.StorageDeviceSets = slices.DeleteFunc(storageCluster.Spec.StorageDeviceSets,
+				func(deviceSet ocsv1.StorageDeviceSet) bool { return deviceSet.Name == e.deviceSetName })
+		}
+		if e.dataPoolName != "" {
+			filesystems := &storageCluster.Spec.ManagedResources.CephFilesystems
+			filesystems.AdditionalDataPools = slices.DeleteFunc(filesystems.AdditionalDataPools,
+				func(dataPool cephv1.NamedPoolSpec) bool { return dataPool.Name == e.dataPoolName })
+		}
+		return e.client.Update(ctx, storageCluster)
+	})
+}
+
+// response returns the objects created or updated by the expansion
+func (e *expansion) response(dryRun bool) (*apiv1.ExpandStorageResponse, error) {
+
+	objects := []client.Object{e.storageCluster}
+	for _, cephBlockPool := range e.cephBlockPools {
+		objects = append(objects, cephBlockPool)
+	}
+	if e.storageClass != nil {
+		objects = append(objects, e.storageClass)
+	}
+
+	response := &apiv1.ExpandStorageResponse{
+		DryRun:        dryRun,
+		Action:        apiv1.DeviceSetCreated,
+		DeviceSetName: e.deviceSetName,
+		Warnings:      append([]string{}, e.warnings...),
+	}
+	if e.scaledBy > 0 {
+		response.Action = apiv1.DeviceSetScaled
+	}
+	for _, obj := range objects {
+		gvk, err := apiutil.GVKForObject(obj, e.client.Scheme())
+		if err != nil {
+			return nil, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(gvk)
+		u.SetManagedFields(nil)
+		response.Objects = append(response.Objects, u)
+	}
+
+	return response, nil
+}
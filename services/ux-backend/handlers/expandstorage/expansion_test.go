@@ -0,0 +1,344 @@
+package expandstorage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	ocsv1 "github.com/red-hat-storage/ocs-operator/api/v4/v1"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const testNamespace = "openshift-storage"
+
+func newTestRequest() *apiv1.ExpandStorageRequest {
+	return &apiv1.ExpandStorageRequest{
+		StorageClassForOSDs: "localblock",
+		DeviceClass:         "ssd2",
+		Storage:             "512Gi",
+		Replica:             3,
+		Count:               1,
+		StorageClusterName:  "ocs-storagecluster",
+		PoolDetails: apiv1.PoolDetails{
+			VolumeType:           volumeTypeBlock,
+			PoolName:             "ssd2-pool",
+			DataProtectionPolicy: 3,
+			FailureDomain:        "host",
+		},
+		StorageClassDetails: apiv1.StorageClassDetails{
+			Name:          "ssd2-rbd",
+			ReclaimPolicy: "Delete",
+		},
+	}
+}
+
+func newTestClientBuilder() *fake.ClientBuilder {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(ocsv1.AddToScheme(scheme))
+	utilruntime.Must(cephv1.AddToScheme(scheme))
+	utilruntime.Must(storagev1.AddToScheme(scheme))
+
+	storageCluster := &ocsv1.StorageCluster{}
+	storageCluster.Name = "ocs-storagecluster"
+	storageCluster.Namespace = testNamespace
+	storageCluster.Spec.StorageDeviceSets = []ocsv1.StorageDeviceSet{{Name: "ocs-deviceset", DeviceClass: "ssd"}}
+
+	localBlock := &storagev1.StorageClass{}
+	localBlock.Name = "localblock"
+
+	existing := &storagev1.StorageClass{}
+	existing.Name = "existing"
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(storageCluster, localBlock, existing)
+}
+
+func TestNewExpansionValidation(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClientBuilder().Build()
+
+	testCases := []struct {
+		label  string
+		mutate func(req *apiv1.ExpandStorageRequest)
+		code   int
+	}{
+		{"invalid quantity", func(req *apiv1.ExpandStorageRequest) { req.Storage = "a lot" }, http.StatusBadRequest},
+		{"missing storageClass for the OSDs", func(req *apiv1.ExpandStorageRequest) { req.StorageClassForOSDs = "missing" }, http.StatusBadRequest},
+		{"missing storageCluster", func(req *apiv1.ExpandStorageRequest) { req.StorageClusterName = "missing" }, http.StatusBadRequest},
+		{"invalid failure domain", func(req *apiv1.ExpandStorageRequest) { req.PoolDetails.FailureDomain = "planet" }, http.StatusBadRequest},
+		{"invalid volume type", func(req *apiv1.ExpandStorageRequest) { req.PoolDetails.VolumeType = "object" }, http.StatusBadRequest},
+		{"filesystem without name", func(req *apiv1.ExpandStorageRequest) { req.PoolDetails.VolumeType = volumeTypeFilesystem }, http.StatusBadRequest},
+		{"invalid reclaim policy", func(req *apiv1.ExpandStorageRequest) { req.StorageClassDetails.ReclaimPolicy = "Recycle" }, http.StatusBadRequest},
+		{"storageClass name in use", func(req *apiv1.ExpandStorageRequest) { req.StorageClassDetails.Name = "existing" }, http.StatusConflict},
+		{"invalid device type", func(req *apiv1.ExpandStorageRequest) { req.DeviceType = "tape" }, http.StatusBadRequest},
+		{"resource request above the limit", func(req *apiv1.ExpandStorageRequest) {
+			req.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			}
+		}, http.StatusBadRequest},
+		{"metadata device without storageClass", func(req *apiv1.ExpandStorageRequest) { req.MetadataDevice = &apiv1.DeviceDetails{Storage: "64Gi"} }, http.StatusBadRequest},
+		{"missing storageClass of the WAL device", func(req *apiv1.ExpandStorageRequest) {
+			req.WalDevice = &apiv1.DeviceDetails{StorageClassName: "missing", Storage: "16Gi"}
+		}, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := newTestRequest()
+			tc.mutate(req)
+			_, err := newExpansion(ctx, cl, testNamespace, req)
+			assert.Error(t, err)
+			assert.Equal(t, tc.code, statusForError(err))
+		})
+	}
+}
+
+func TestExpansionDryRun(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClientBuilder().Build()
+
+	e, err := newExpansion(ctx, cl, testNamespace, newTestRequest())
+	assert.NoError(t, err)
+
+	response, err := e.response(true)
+	assert.NoError(t, err)
+	assert.True(t, response.DryRun)
+	assert.Len(t, response.Objects, 3)
+	assert.Equal(t, []string{"StorageCluster", "CephBlockPool", "StorageClass"},
+		[]string{response.Objects[0].GetKind(), response.Objects[1].GetKind(), response.Objects[2].GetKind()})
+
+	// the cluster is not changed
+	storageCluster := &ocsv1.StorageCluster{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	assert.Len(t, storageCluster.Spec.StorageDeviceSets, 1)
+	assert.True(t, k8serrors.IsNotFound(cl.Get(ctx, client.ObjectKey{Name: "ssd2-pool", Namespace: testNamespace}, &cephv1.CephBlockPool{})))
+}
+
+func TestExpansionApply(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClientBuilder().Build()
+
+	req := newTestRequest()
+	req.PoolDetails.VolumeType = volumeTypeFilesystem
+	req.PoolDetails.FilesystemName = "ocs-storagecluster-cephfilesystem"
+	e, err := newExpansion(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.NoError(t, e.apply(ctx))
+
+	storageCluster := &ocsv1.StorageCluster{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	assert.Len(t, storageCluster.Spec.StorageDeviceSets, 2)
+	assert.Len(t, storageCluster.Spec.ManagedResources.CephFilesystems.AdditionalDataPools, 1)
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ssd2-rbd"}, &storagev1.StorageClass{}))
+}
+
+func TestExpansionRollback(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if _, ok := obj.(*storagev1.StorageClass); ok {
+				return errors.New("create failed")
+			}
+			return cl.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	e, err := newExpansion(ctx, cl, testNamespace, newTestRequest())
+	assert.NoError(t, err)
+	assert.Error(t, e.apply(ctx))
+
+	// the device set and the pool are removed again
+	storageCluster := &ocsv1.StorageCluster{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	assert.Len(t, storageCluster.Spec.StorageDeviceSets, 1)
+	assert.True(t, k8serrors.IsNotFound(cl.Get(ctx, client.ObjectKey{Name: "ssd2-pool", Namespace: testNamespace}, &cephv1.CephBlockPool{})))
+}
+
+func TestExpansionErasureCoded(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClientBuilder().WithStatusSubresource(&ocsv1.StorageCluster{}).Build()
+
+	storageCluster := &ocsv1.StorageCluster{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	storageCluster.Status.FailureDomain = "host"
+	storageCluster.Status.FailureDomainValues = []string{"node-a", "node-b", "node-c", "node-d"}
+	assert.NoError(t, cl.Status().Update(ctx, storageCluster))
+
+	// 2+2 chunks require 4 hosts, the new device set has 3 OSDs
+	req := newTestRequest()
+	req.PoolDetails.ErasureCoded = &apiv1.ErasureCodedDetails{DataChunks: 2, CodingChunks: 2}
+	_, err := newExpansion(ctx, cl, testNamespace, req)
+	assert.Equal(t, http.StatusBadRequest, statusForError(err))
+
+	req.Replica = 4
+	e, err := newExpansion(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.Len(t, e.cephBlockPools, 2)
+	assert.Equal(t, "ssd2-pool-metadata", e.cephBlockPools[0].Name)
+	assert.Equal(t, uint(3), e.cephBlockPools[0].Spec.Replicated.Size)
+	assert.Equal(t, uint(2), e.cephBlockPools[1].Spec.ErasureCoded.CodingChunks)
+	assert.Equal(t, uint(0), e.cephBlockPools[1].Spec.Replicated.Size)
+	assert.Equal(t, "ssd2-pool-metadata", e.storageClass.Parameters["pool"])
+	assert.Equal(t, "ssd2-pool", e.storageClass.Parameters["dataPool"])
+
+	// a filesystem data pool needs no metadata pool
+	req.PoolDetails.VolumeType = volumeTypeFilesystem
+	req.PoolDetails.FilesystemName = "ocs-storagecluster-cephfilesystem"
+	e, err = newExpansion(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.Empty(t, e.cephBlockPools)
+	dataPools := e.storageCluster.Spec.ManagedResources.CephFilesystems.AdditionalDataPools
+	assert.Equal(t, uint(2), dataPools[0].ErasureCoded.DataChunks)
+
+	// the number of racks is unknown
+	req.PoolDetails.FailureDomain = "rack"
+	_, err = newExpansion(ctx, cl, testNamespace, req)
+	assert.Equal(t, http.StatusBadRequest, statusForError(err))
+}
+
+func TestExpansionScaleDeviceSet(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClientBuilder().Build()
+
+	e, err := newExpansion(ctx, cl, testNamespace, newTestRequest())
+	assert.NoError(t, err)
+	response, err := e.response(true)
+	assert.NoError(t, err)
+	assert.Empty(t, response.Warnings)
+	assert.NoError(t, e.apply(ctx))
+
+	// the same request again scales the device set, the pool and the StorageClass already exist
+	e, err = newExpansion(ctx, cl, testNamespace, newTestRequest())
+	assert.NoError(t, err)
+	response, err = e.response(true)
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.DeviceSetScaled, response.Action)
+	assert.Equal(t, "ssd2", response.DeviceSetName)
+	assert.Len(t, response.Objects, 1)
+	// the ignored pool and StorageClass details are reported
+	if assert.Len(t, response.Warnings, 2) {
+		assert.Contains(t, response.Warnings[0], "poolDetails are ignored")
+		assert.Contains(t, response.Warnings[1], "storageClassDetails are ignored")
+	}
+	assert.NoError(t, e.apply(ctx))
+
+	storageCluster := &ocsv1.StorageCluster{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	assert.Len(t, storageCluster.Spec.StorageDeviceSets, 2)
+	assert.Equal(t, 2, storageCluster.Spec.StorageDeviceSets[1].Count)
+
+	// the device set is scaled back when the update cannot be undone otherwise
+	assert.NoError(t, e.revertStorageCluster(ctx))
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	assert.Equal(t, 1, storageCluster.Spec.StorageDeviceSets[1].Count)
+
+	// other attributes add a device set of the device class
+	req := newTestRequest()
+	req.Storage = "1Ti"
+	e, err = newExpansion(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	response, err = e.response(true)
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.DeviceSetCreated, response.Action)
+	assert.Equal(t, "ssd2-1", response.DeviceSetName)
+	assert.Len(t, response.Objects, 1)
+	assert.Len(t, response.Warnings, 2)
+
+	// a request without the details has no warnings
+	req.PoolDetails = apiv1.PoolDetails{}
+	req.StorageClassDetails = apiv1.StorageClassDetails{}
+	e, err = newExpansion(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	response, err = e.response(true)
+	assert.NoError(t, err)
+	assert.Empty(t, response.Warnings)
+}
+
+func TestValidateAvailableVolumes(t *testing.T) {
+	ctx := context.Background()
+
+	newPV := func(name, storageClassName, size string, phase corev1.PersistentVolumePhase) *corev1.PersistentVolume {
+		pv := &corev1.PersistentVolume{}
+		pv.Name = name
+		pv.Spec.StorageClassName = storageClassName
+		pv.Spec.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)}
+		pv.Status.Phase = phase
+		return pv
+	}
+	cl := newTestClientBuilder().WithObjects(
+		newPV("pv-1", "localblock", "512Gi", corev1.VolumeAvailable),
+		newPV("pv-2", "localblock", "1Ti", corev1.VolumeAvailable),
+		newPV("pv-3", "localblock", "256Gi", corev1.VolumeAvailable),
+		newPV("pv-4", "localblock", "512Gi", corev1.VolumeBound),
+		newPV("pv-5", "other", "512Gi", corev1.VolumeAvailable),
+	).Build()
+
+	localBlock := &storagev1.StorageClass{Provisioner: noProvisioner}
+	localBlock.Name = "localblock"
+	size := resource.MustParse("512Gi")
+
+	assert.NoError(t, validateAvailableVolumes(ctx, cl, localBlock, size, 2))
+	err := validateAvailableVolumes(ctx, cl, localBlock, size, 3)
+	assert.Equal(t, http.StatusBadRequest, statusForError(err))
+
+	// dynamically provisioned PVs are not checked
+	localBlock.Provisioner = "ebs.csi.aws.com"
+	assert.NoError(t, validateAvailableVolumes(ctx, cl, localBlock, size, 3))
+}
+
+func TestExpansionDeviceSetAttributes(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClientBuilder().Build()
+
+	// HDD OSDs with their metadata on NVMe devices
+	req := newTestRequest()
+	req.DeviceType = "hdd"
+	req.Portable = true
+	req.MetadataDevice = &apiv1.DeviceDetails{StorageClassName: "existing", Storage: "64Gi"}
+	req.Resources = &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("5Gi")}}
+	e, err := newExpansion(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.NoError(t, e.apply(ctx))
+
+	storageCluster := &ocsv1.StorageCluster{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	deviceSet := storageCluster.Spec.StorageDeviceSets[1]
+	assert.Equal(t, "HDD", deviceSet.DeviceType)
+	assert.True(t, deviceSet.Portable)
+	assert.True(t, deviceSet.Config.TuneSlowDeviceClass)
+	assert.Equal(t, "existing", *deviceSet.MetadataPVCTemplate.Spec.StorageClassName)
+	assert.Equal(t, "64Gi", deviceSet.MetadataPVCTemplate.Spec.Resources.Requests.Storage().String())
+	assert.Nil(t, deviceSet.WalPVCTemplate)
+	assert.Equal(t, "5Gi", deviceSet.Resources.Limits.Memory().String())
+
+	// a device set without the metadata device is another device set of the device class
+	req.MetadataDevice = nil
+	e, err = newExpansion(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "ssd2-1", e.deviceSetName)
+
+	// local volumes cannot move to another node
+	localBlock := &storagev1.StorageClass{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "localblock"}, localBlock))
+	localBlock.Provisioner = noProvisioner
+	assert.NoError(t, cl.Update(ctx, localBlock))
+	_, err = newExpansion(ctx, cl, testNamespace, req)
+	assert.Equal(t, http.StatusBadRequest, statusForError(err))
+	assert.ErrorContains(t, err, "portable OSDs")
+}
@@ -2,21 +2,21 @@ package expandstorage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"html"
 	"net/http"
 	"strconv"
 
 	ocsv1 "github.com/red-hat-storage/ocs-operator/api/v4/v1"
-	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,167 +33,158 @@ func HandleMessage(w http.ResponseWriter, r *http.Request, client client.Client,
 
 func handlePost(w http.ResponseWriter, r *http.Request, client client.Client, namespace string) {
 	// When ContentLength is 0 that means request body is empty
-	var err error
 	if r.ContentLength == 0 {
 		klog.Errorf("body in the request is required")
-		http.Error(w, "body in the request is required", http.StatusBadRequest)
+		util.HTTPError(w, r, "body in the request is required", http.StatusBadRequest)
 		return
 	}
 
-	type poolDetails struct {
-		VolumeType           string `json:"volumeType"`
-		PoolName             string `json:"poolName"`
-		DataProtectionPolicy int    `json:"dataProtectionPolicy"`
-		EnableCompression    bool   `json:"enableCompression"`
-		FilesystemName       string `json:"filesystemName"`
-		FailureDomain        string `json:"failureDomain"`
-	}
+	var ExpandStorage apiv1.ExpandStorageRequest
 
-	type storageClassDetails struct {
-		ReclaimPolicy                string `json:"reclaimPolicy"`
-		Name                         string `json:"name"`
-		VolumeBindingMode            string `json:"volumeBindingMode"`
-		EnableStorageClassEncryption bool   `json:"enableStorageClassEncryption"`
-		EncryptionKMSID              string `json:"encryptionKMSID"`
+	if err := json.NewDecoder(r.Body).Decode(&ExpandStorage); err != nil {
+		util.HTTPError(w, r, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	var ExpandStorage = struct {
-		StorageClassForOSDs string              `json:"storageClassForOSDs"`
-		DeviceClass         string              `json:"deviceClass"`
-		EnableEncryption    bool                `json:"enableEncryption"`
-		Storage             string              `json:"storage"`
-		Replica             int                 `json:"replica"`
-		Count               int                 `json:"count"`
-		StorageClusterName  string              `json:"storageClusterName"`
-		PoolDetails         poolDetails         `json:"poolDetails"`
-		StorageClassDetails storageClassDetails `json:"storageClassDetails"`
-	}{}
-
-	if err = json.NewDecoder(r.Body).Decode(&ExpandStorage); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	// the user has to be allowed to perform the changes which are made on its behalf
+	actions := []authorizationv1.ResourceAttributes{
+		{Group: "ocs.openshift.io", Resource: "storageclusters", Namespace: namespace, Name: ExpandStorage.StorageClusterName, Verb: "update"},
+		{Group: "storage.k8s.io", Resource: "storageclasses", Verb: "create"},
+	}
+	if ExpandStorage.PoolDetails.VolumeType == "block" {
+		actions = append(actions, authorizationv1.ResourceAttributes{Group: "ceph.rook.io", Resource: "cephblockpools", Namespace: namespace, Verb: "create"})
+	}
+	if !util.AuthorizeRequest(w, r, client, actions...) {
 		return
 	}
 
-	storageCluster := &ocsv1.StorageCluster{}
-	err = client.Get(r.Context(), types.NamespacedName{Name: ExpandStorage.StorageClusterName, Namespace: namespace}, storageCluster)
+	// the whole request is validated before the cluster is changed
+	expansion, err := newExpansion(r.Context(), client, namespace, &ExpandStorage)
 	if err != nil {
-		klog.Errorf("failed to get storageCluster: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		klog.Errorf("invalid expandstorage request: %v", err)
+		util.HTTPError(w, r, err.Error(), statusForError(err))
 		return
 	}
 
-	// update storageCluster
-	updateStorageCluster(w, r, client, ExpandStorage.StorageClassForOSDs, ExpandStorage.DeviceClass, ExpandStorage.EnableEncryption, ExpandStorage.Storage, ExpandStorage.Count, ExpandStorage.Replica, storageCluster)
+	if !ExpandStorage.DryRun {
+		if err := expansion.apply(r.Context()); err != nil {
+			klog.Errorf("failed to expand storageCluster %q: %v", ExpandStorage.StorageClusterName, err)
+			util.HTTPError(w, r, err.Error(), statusForError(err))
+			return
+		}
+	}
 
-	dataProtectionPolicy, err := util.SafeIntToUint(ExpandStorage.PoolDetails.DataProtectionPolicy)
+	response, err := expansion.response(ExpandStorage.DryRun)
 	if err != nil {
-		klog.Errorf("failed to convert dataProtectionPolicy to uint: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		klog.Errorf("failed to build response: %v", err)
+		util.HTTPError(w, r, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	switch ExpandStorage.PoolDetails.VolumeType {
-	case "block":
-		klog.Info("Creating cephBlockPool and storageClass")
-		// create cephBlockPool
-		createCephBlockPool(w, r, client, ExpandStorage.PoolDetails.PoolName, ExpandStorage.DeviceClass, dataProtectionPolicy, ExpandStorage.PoolDetails.EnableCompression, namespace, ExpandStorage.PoolDetails.FailureDomain, storageCluster.Spec.Arbiter.Enable)
-
-		// create storageClass
-		createCephBlockPoolStorageClass(w, r, client, ExpandStorage.StorageClassDetails.Name, ExpandStorage.PoolDetails.PoolName, ExpandStorage.StorageClassDetails.ReclaimPolicy, ExpandStorage.StorageClassDetails.VolumeBindingMode, ExpandStorage.StorageClassDetails.EnableStorageClassEncryption, ExpandStorage.StorageClassDetails.EncryptionKMSID)
-
-	case "filesystem":
-		klog.Info("Creating cephFilesystem dataPool and storageClass")
-		// create cephFilesystem dataPool
-		createCephFilesystemDataPool(w, r, client, ExpandStorage.PoolDetails.PoolName, ExpandStorage.DeviceClass, dataProtectionPolicy, ExpandStorage.PoolDetails.EnableCompression, ExpandStorage.PoolDetails.FailureDomain, storageCluster)
-
-		// create storageClass
-		createCephFilesystemStorageClass(w, r, client, ExpandStorage.StorageClassDetails.Name, ExpandStorage.PoolDetails.PoolName, ExpandStorage.StorageClassDetails.ReclaimPolicy, ExpandStorage.StorageClassDetails.VolumeBindingMode, ExpandStorage.PoolDetails.FilesystemName)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		klog.Errorf("failed to encode response: %v", err)
+	}
+}
 
-	default:
-		klog.Errorf("invalid volumeType: %s", ExpandStorage.PoolDetails.VolumeType)
-		http.Error(w, "invalid volumeType", http.StatusBadRequest)
-		return
+// statusForError returns the status code of the response to an error
+func statusForError(err error) int {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.code
+	}
+	var apiStatus interface{ Status() metav1.Status }
+	if errors.As(err, &apiStatus) && apiStatus.Status().Code != 0 {
+		return int(apiStatus.Status().Code)
 	}
+	return http.StatusInternalServerError
+}
 
+func newStorageDeviceSet(storageClassForOSDs string, deviceClass string, enableEncryption bool, storageQty resource.Quantity, count, replica int) ocsv1.StorageDeviceSet {
+	return ocsv1.StorageDeviceSet{
+		Name:            deviceClass,
+		Count:           count,
+		Replica:         replica,
+		Portable:        false,
+		Encrypted:       &enableEncryption,
+		DeviceClass:     deviceClass,
+		DeviceType:      "SSD",
+		DataPVCTemplate: newDevicePVCTemplate(storageClassForOSDs, storageQty),
+	}
 }
 
-func updateStorageCluster(w http.ResponseWriter, r *http.Request, client client.Client, storageClassForOSDs string, deviceClass string, enableEncryption bool, storage string, count, replica int, storageCluster *ocsv1.StorageCluster) {
-	klog.Infof("Updating storageCluster %q", storageCluster.Name)
-	storageQty := resource.MustParse(storage)
+// newDevicePVCTemplate returns the template of the PVCs of a device of the OSDs
+func newDevicePVCTemplate(storageClassName string, storageQty resource.Quantity) corev1.PersistentVolumeClaim {
 	volumeMode := corev1.PersistentVolumeBlock
-	deviceSet := ocsv1.StorageDeviceSet{
-		Name:        deviceClass,
-		Count:       count,
-		Replica:     replica,
-		Portable:    false,
-		Encrypted:   &enableEncryption,
-		DeviceClass: deviceClass,
-		DeviceType:  "SSD",
-		DataPVCTemplate: corev1.PersistentVolumeClaim{
-			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
-				StorageClassName: &storageClassForOSDs,
-				VolumeMode:       &volumeMode,
-				Resources: corev1.VolumeResourceRequirements{
-					Requests: corev1.ResourceList{
-						corev1.ResourceStorage: storageQty,
-					},
+	return corev1.PersistentVolumeClaim{
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+			StorageClassName: &storageClassName,
+			VolumeMode:       &volumeMode,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: storageQty,
 				},
 			},
 		},
 	}
-
-	storageCluster.Spec.StorageDeviceSets = append(storageCluster.Spec.StorageDeviceSets, deviceSet)
-	err := client.Update(r.Context(), storageCluster)
-	if err != nil {
-		klog.Errorf("failed to update storageCluster: %q", storageCluster.Name)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
 }
 
-func createCephBlockPool(w http.ResponseWriter, r *http.Request, client client.Client, poolName, deviceClass string, dataProtectionPolicy uint, enableCompression bool, namespace, failureDomain string, arbiter bool) {
+// newPoolSpec returns an erasure coded pool spec if the profile is set, and a replicated one otherwise
+func newPoolSpec(deviceClass string, dataProtectionPolicy uint, erasureCoded *apiv1.ErasureCodedDetails, enableCompression bool, failureDomain string) cephv1.PoolSpec {
 	compression := "none"
 	if enableCompression {
 		compression = "aggressive"
 	}
-	var replicasPerFailureDomain uint = 1
-	if arbiter {
-		replicasPerFailureDomain = 2
+
+	poolSpec := cephv1.PoolSpec{
+		FailureDomain: failureDomain,
+		DeviceClass:   deviceClass,
+		Parameters: map[string]string{
+			"compression_mode": compression,
+		},
+		EnableCrushUpdates: ptr.To(true),
+	}
+	if erasureCoded != nil {
+		poolSpec.ErasureCoded = cephv1.ErasureCodedSpec{
+			DataChunks:   erasureCoded.DataChunks,
+			CodingChunks: erasureCoded.CodingChunks,
+			Algorithm:    erasureCoded.Algorithm,
+		}
+	} else {
+		poolSpec.Replicated = cephv1.ReplicatedSpec{
+			Size:                   dataProtectionPolicy,
+			RequireSafeReplicaSize: true,
+		}
+	}
+
+	return poolSpec
+}
+
+func newCephBlockPool(poolName, namespace string, poolSpec cephv1.PoolSpec, arbiter bool) *cephv1.CephBlockPool {
+	poolSpec.EnableRBDStats = true
+	if poolSpec.Replicated.Size > 0 {
+		poolSpec.Replicated.ReplicasPerFailureDomain = 1
+		if arbiter {
+			poolSpec.Replicated.ReplicasPerFailureDomain = 2
+		}
 	}
 
-	cephBlockPool := &cephv1.CephBlockPool{
+	return &cephv1.CephBlockPool{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      poolName,
 			Namespace: namespace,
 		},
 		Spec: cephv1.NamedBlockPoolSpec{
-			PoolSpec: cephv1.PoolSpec{
-				FailureDomain:  failureDomain,
-				EnableRBDStats: true,
-				DeviceClass:    deviceClass,
-				Replicated: cephv1.ReplicatedSpec{
-					Size:                     dataProtectionPolicy,
-					RequireSafeReplicaSize:   true,
-					ReplicasPerFailureDomain: replicasPerFailureDomain,
-				},
-				Parameters: map[string]string{
-					"compression_mode": compression,
-				},
-				EnableCrushUpdates: ptr.To(true),
-			},
+			PoolSpec: poolSpec,
 		},
 	}
-
-	err := client.Create(r.Context(), cephBlockPool)
-	if err != nil {
-		klog.Errorf("failed to create cephBlockPool: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
 }
 
-func createCephBlockPoolStorageClass(w http.ResponseWriter, r *http.Request, client client.Client, storageClassName, poolName, reclaimPolicy, volumeBindingMode string, enableEncryption bool, encryptionKMSID string) {
+// newCephBlockPoolStorageClass returns the StorageClass of a pool. For an erasure coded pool, the images are stored in the
+// replicated metadata pool and their data in the erasure coded data pool.
+func newCephBlockPoolStorageClass(storageClassName, poolName, dataPoolName string, reclaimPolicy *corev1.PersistentVolumeReclaimPolicy, volumeBindingMode *storagev1.VolumeBindingMode, enableEncryption bool, encryptionKMSID string) *storagev1.StorageClass {
 	allowVolumeExpansion := true
 	storageClass := &storagev1.StorageClass{
 		ObjectMeta: metav1.ObjectMeta{
@@ -204,8 +195,8 @@ func createCephBlockPoolStorageClass(w http.ResponseWriter, r *http.Request, cli
 			},
 		},
 		Provisioner:       util.RbdDriverName,
-		ReclaimPolicy:     (*corev1.PersistentVolumeReclaimPolicy)(&reclaimPolicy),
-		VolumeBindingMode: (*storagev1.VolumeBindingMode)(&volumeBindingMode),
+		ReclaimPolicy:     reclaimPolicy,
+		VolumeBindingMode: volumeBindingMode,
 		// AllowVolumeExpansion is set to true to enable expansion of OCS backed Volumes
 		AllowVolumeExpansion: &allowVolumeExpansion,
 		Parameters: map[string]string{
@@ -216,53 +207,27 @@ func createCephBlockPoolStorageClass(w http.ResponseWriter, r *http.Request, cli
 			"encrypted":                 strconv.FormatBool(enableEncryption),
 		},
 	}
+	if dataPoolName != "" {
+		storageClass.Parameters["dataPool"] = dataPoolName
+	}
 	if enableEncryption {
 		storageClass.Parameters["encryptionKMSID"] = encryptionKMSID
 		storageClass.Annotations["keyrotation.csiaddons.openshift.io/schedule"] = "@weekly"
 	}
 
-	err := client.Create(r.Context(), storageClass)
-	if err != nil {
-		klog.Errorf("failed to create storageClass: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	return storageClass
 }
 
-func createCephFilesystemDataPool(w http.ResponseWriter, r *http.Request, client client.Client, poolName, deviceClass string, dataProtectionPolicy uint, enableCompression bool, failureDomain string, storageCluster *ocsv1.StorageCluster) {
-	compression := "none"
-	if enableCompression {
-		compression = "aggressive"
-	}
-
-	datapool := cephv1.NamedPoolSpec{
-		Name: poolName,
-		PoolSpec: cephv1.PoolSpec{
-			FailureDomain: failureDomain,
-			DeviceClass:   deviceClass,
-			Replicated: cephv1.ReplicatedSpec{
-				Size:                   dataProtectionPolicy,
-				RequireSafeReplicaSize: true,
-			},
-			Parameters: map[string]string{
-				"compression_mode": compression,
-			},
-			EnableCrushUpdates: ptr.To(true),
-		},
-	}
-
-	storageCluster.Spec.ManagedResources.CephFilesystems.AdditionalDataPools = append(storageCluster.Spec.ManagedResources.CephFilesystems.AdditionalDataPools, datapool)
-	err := client.Update(r.Context(), storageCluster)
-	if err != nil {
-		klog.Errorf("failed to update storageCluster: %q", storageCluster.Name)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+func newCephFilesystemDataPool(poolName string, poolSpec cephv1.PoolSpec) cephv1.NamedPoolSpec {
+	return cephv1.NamedPoolSpec{
+		Name:     poolName,
+		PoolSpec: poolSpec,
 	}
 }
 
-func createCephFilesystemStorageClass(w http.ResponseWriter, r *http.Request, client client.Client, storageClassName, poolName, reclaimPolicy, volumeBindingMode string, filesystemName string) {
+func newCephFilesystemStorageClass(storageClassName, poolName string, reclaimPolicy *corev1.PersistentVolumeReclaimPolicy, volumeBindingMode *storagev1.VolumeBindingMode, filesystemName string) *storagev1.StorageClass {
 	allowVolumeExpansion := true
-	storageClass := &storagev1.StorageClass{
+	return &storagev1.StorageClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: storageClassName,
 			Annotations: map[string]string{
@@ -270,8 +235,8 @@ func createCephFilesystemStorageClass(w http.ResponseWriter, r *http.Request, cl
 			},
 		},
 		Provisioner:       util.CephFSDriverName,
-		ReclaimPolicy:     (*corev1.PersistentVolumeReclaimPolicy)(&reclaimPolicy),
-		VolumeBindingMode: (*storagev1.VolumeBindingMode)(&volumeBindingMode),
+		ReclaimPolicy:     reclaimPolicy,
+		VolumeBindingMode: volumeBindingMode,
 		// AllowVolumeExpansion is set to true to enable expansion of OCS backed Volumes
 		AllowVolumeExpansion: &allowVolumeExpansion,
 		Parameters: map[string]string{
@@ -279,23 +244,8 @@ func createCephFilesystemStorageClass(w http.ResponseWriter, r *http.Request, cl
 			"pool":   fmt.Sprintf("%s-%s", filesystemName, poolName),
 		},
 	}
-
-	err := client.Create(r.Context(), storageClass)
-	if err != nil {
-		klog.Errorf("failed to create storageClass: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
 }
 
 func handleUnsupportedMethod(w http.ResponseWriter, r *http.Request) {
-	klog.Infof("Only POST method should be used to send data to this endpoint %s", r.URL.Path)
-	w.WriteHeader(http.StatusMethodNotAllowed)
-	w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
-	w.Header().Set("Allow", "POST")
-
-	if _, err := fmt.Fprintf(w, "Unsupported method : %s", html.EscapeString(r.Method)); err != nil {
-		klog.Errorf("failed write data to response writer: %v", err)
-	}
-
+	util.HTTPErrorUnsupportedMethod(w, r, "POST")
 }
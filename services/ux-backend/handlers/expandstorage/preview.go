@@ -0,0 +1,453 @@
+package expandstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	ocsv1 "github.com/red-hat-storage/ocs-operator/api/v4/v1"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
+	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// storageNodeLabel is the label of the storage nodes, unless the StorageCluster has a label selector
+	storageNodeLabel = "cluster.ocs.openshift.io/openshift-storage"
+
+	// defaultReplicaSize is the replica size of a pool when the request does not set the dataProtectionPolicy
+	defaultReplicaSize = 3
+
+	// discoveredDevicesPrefix is the name prefix of the ConfigMaps with the devices discovered on each node
+	discoveredDevicesPrefix = "devicefinder-result-"
+)
+
+func HandlePreviewMessage(w http.ResponseWriter, r *http.Request, client client.Client, namespace string) {
+	switch r.Method {
+	case "POST":
+		handlePreviewPost(w, r, client, namespace)
+	default:
+		util.HTTPErrorUnsupportedMethod(w, r, "POST")
+	}
+}
+
+func handlePreviewPost(w http.ResponseWriter, r *http.Request, client client.Client, namespace string) {
+	// When ContentLength is 0 that means request body is empty
+	if r.ContentLength == 0 {
+		klog.Errorf("body in the request is required")
+		util.HTTPError(w, r, "body in the request is required", http.StatusBadRequest)
+		return
+	}
+
+	var ExpandStorage apiv1.ExpandStorageRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&ExpandStorage); err != nil {
+		util.HTTPError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !util.AuthorizeRequest(w, r, client,
+		authorizationv1.ResourceAttributes{Group: "ocs.openshift.io", Resource: "storageclusters", Namespace: namespace, Name: ExpandStorage.StorageClusterName, Verb: "get"},
+		authorizationv1.ResourceAttributes{Resource: "nodes", Verb: "list"}) {
+		return
+	}
+
+	preview, err := newPreview(r.Context(), client, namespace, &ExpandStorage)
+	if err != nil {
+		klog.Errorf("failed to preview the expansion of storageCluster %q: %v", ExpandStorage.StorageClusterName, err)
+		util.HTTPError(w, r, err.Error(), statusForError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(preview); err != nil {
+		klog.Errorf("failed to encode response: %v", err)
+	}
+}
+
+// newPreview projects the capacity of the device class and the placement of its OSDs after the expansion, the cluster
+// is not changed. The problems of the expansion are returned as warnings, only a request which cannot be previewed is
+// an error.
+func newPreview(ctx context.Context, cl client.Client, namespace string, req *apiv1.ExpandStorageRequest) (*apiv1.ExpandStoragePreviewResponse, error) {
+
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	deviceSet, err := newRequestDeviceSet(req)
+	if err != nil {
+		return nil, err
+	}
+	storageQty := *deviceSet.DataPVCTemplate.Spec.Resources.Requests.Storage()
+
+	storageCluster, err := getStorageCluster(ctx, cl, namespace, req.StorageClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// a missing StorageClass is reported by the validation of the expansion
+	osdStorageClass := &storagev1.StorageClass{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: req.StorageClassForOSDs}, osdStorageClass); k8serrors.IsNotFound(err) {
+		osdStorageClass = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get storageClass: %w", err)
+	}
+
+	preview := &apiv1.ExpandStoragePreviewResponse{
+		Action:        apiv1.DeviceSetCreated,
+		NewOSDs:       req.Count * req.Replica,
+		FailureDomain: req.PoolDetails.FailureDomain,
+		Placement:     []apiv1.FailureDomainPlacement{},
+		Warnings:      []string{},
+	}
+	if preview.FailureDomain == "" {
+		preview.FailureDomain = storageCluster.Status.FailureDomain
+	}
+
+	deviceSets := storageCluster.Spec.StorageDeviceSets
+	if i, _ := findDeviceSet(deviceSets, &deviceSet); i != -1 {
+		preview.Action = apiv1.DeviceSetScaled
+		preview.DeviceSetName = deviceSets[i].Name
+	} else {
+		preview.DeviceSetName = newDeviceSetName(deviceSets, req.DeviceClass)
+	}
+
+	var currentOSDs int
+	var currentRaw int64
+	for _, deviceSet := range deviceSets {
+		if deviceSet.DeviceClass == req.DeviceClass {
+			currentOSDs += deviceSet.Count * deviceSet.Replica
+			currentRaw += deviceSet.DataPVCTemplate.Spec.Resources.Requests.Storage().Value() * int64(deviceSet.Count*deviceSet.Replica)
+		}
+	}
+	addedRaw := storageQty.Value() * int64(preview.NewOSDs)
+
+	chunks, dataRatio := poolChunks(req, storageCluster)
+	currentEfficiency, projectedEfficiency := 1.0, 1.0
+
+	if preview.FailureDomain == "osd" {
+		if osds := currentOSDs + preview.NewOSDs; osds < chunks {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("the pool requires %d OSDs of device class %q, there are %d",
+				chunks, req.DeviceClass, osds))
+		}
+	} else {
+		unplaced, err := previewPlacement(ctx, cl, namespace, storageCluster, osdStorageClass, preview, req.DeviceClass, storageQty, req.Count, req.Replica)
+		if err != nil {
+			return nil, err
+		}
+		if len(preview.Placement) == 0 {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("the %q failure domains of the storage nodes are unknown, the placement of the OSDs is not previewed",
+				preview.FailureDomain))
+		} else {
+			currentEfficiency, projectedEfficiency = placementWarnings(preview, req.DeviceClass, chunks, unplaced, storageQty)
+		}
+	}
+
+	usable := func(raw int64, efficiency float64) int64 {
+		return int64(float64(raw) * dataRatio * efficiency)
+	}
+	currentUsable := usable(currentRaw, currentEfficiency)
+	projectedUsable := usable(currentRaw+addedRaw, projectedEfficiency)
+	preview.RawCapacity = *resource.NewQuantity(currentRaw+addedRaw, resource.BinarySI)
+	preview.AddedRawCapacity = *resource.NewQuantity(addedRaw, resource.BinarySI)
+	preview.UsableCapacity = *resource.NewQuantity(projectedUsable, resource.BinarySI)
+	preview.AddedUsableCapacity = *resource.NewQuantity(max(projectedUsable-currentUsable, 0), resource.BinarySI)
+
+	// the request is validated like it would be when it is applied
+	if _, err := newExpansion(ctx, cl, namespace, req); err != nil {
+		var reqErr *requestError
+		if !errors.As(err, &reqErr) {
+			return nil, err
+		}
+		preview.Warnings = append(preview.Warnings, "the request would be rejected: "+reqErr.message)
+	}
+
+	return preview, nil
+}
+
+// poolChunks returns the number of failure domains each object of the pool is stored in, and the ratio of the raw
+// capacity which stores data
+func poolChunks(req *apiv1.ExpandStorageRequest, storageCluster *ocsv1.StorageCluster) (int, float64) {
+
+	if profile := req.PoolDetails.ErasureCoded; profile != nil && profile.DataChunks > 0 {
+		chunks := int(profile.DataChunks + profile.CodingChunks)
+		return chunks, float64(profile.DataChunks) / float64(chunks)
+	}
+
+	size := req.PoolDetails.DataProtectionPolicy
+	if size <= 0 {
+		size = defaultReplicaSize
+	}
+	// a stretched cluster keeps 2 replicas in each zone
+	if storageCluster.Spec.Arbiter.Enable {
+		return max(size/2, 1), 1 / float64(size)
+	}
+	return size, 1 / float64(size)
+}
+
+// previewPlacement sets the placement of the preview, each new OSD is placed in the failure domain with the fewest OSDs
+// of the device class which has an available device. It returns the number of OSDs which cannot be placed.
+func previewPlacement(ctx context.Context, cl client.Client, namespace string, storageCluster *ocsv1.StorageCluster,
+	osdStorageClass *storagev1.StorageClass, preview *apiv1.ExpandStoragePreviewResponse, deviceClass string, size resource.Quantity, count, replica int) (int, error) {
+
+	labelKey := failureDomainLabel(storageCluster, preview.FailureDomain)
+	if labelKey == "" {
+		return 0, nil
+	}
+
+	selector := labels.NewSelector()
+	if storageCluster.Spec.LabelSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(storageCluster.Spec.LabelSelector); err != nil {
+			return 0, fmt.Errorf("invalid label selector of storageCluster %q: %w", storageCluster.Name, err)
+		}
+	} else {
+		requirement, err := labels.NewRequirement(storageNodeLabel, selection.Exists, nil)
+		if err != nil {
+			return 0, err
+		}
+		selector = selector.Add(*requirement)
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := cl.List(ctx, nodeList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return 0, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	domains := map[string]*apiv1.FailureDomainPlacement{}
+	nodeDomains := map[string]string{}
+	hostnames := map[string]string{}
+	for _, node := range nodeList.Items {
+		domain, ok := node.Labels[labelKey]
+		if !ok {
+			continue
+		}
+		if domains[domain] == nil {
+			domains[domain] = &apiv1.FailureDomainPlacement{Name: domain}
+		}
+		nodeDomains[node.Name] = domain
+		hostnames[node.Labels[corev1.LabelHostname]] = node.Name
+	}
+	if len(domains) == 0 {
+		return 0, nil
+	}
+
+	// rook labels the OSDs with the location of their node
+	deploymentList := &appsv1.DeploymentList{}
+	if err := cl.List(ctx, deploymentList, client.InNamespace(namespace),
+		client.MatchingLabels{"app": "rook-ceph-osd", "device-class": deviceClass}); err != nil {
+		return 0, fmt.Errorf("failed to list OSD deployments: %w", err)
+	}
+	for _, deployment := range deploymentList.Items {
+		if domain := domains[deployment.Labels["topology-location-"+preview.FailureDomain]]; domain != nil {
+			domain.CurrentOSDs++
+		}
+	}
+
+	// the PVs of local volumes are created up front, on the nodes of the devices
+	if osdStorageClass != nil && osdStorageClass.Provisioner == noProvisioner {
+		devices, err := countAvailableDevices(ctx, cl, namespace, osdStorageClass.Name, size, hostnames)
+		if err != nil {
+			return 0, err
+		}
+		available := map[string]int{}
+		for nodeName, count := range devices {
+			if domain, ok := nodeDomains[nodeName]; ok {
+				available[domain] += count
+			}
+		}
+		for name, domain := range domains {
+			domain.AvailableDevices = ptr.To(available[name])
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(domains)) {
+		preview.Placement = append(preview.Placement, *domains[name])
+	}
+
+	// the replicas of a device set are spread over different failure domains
+	unplaced := 0
+	for range count {
+		used := map[int]bool{}
+		for range replica {
+			next := -1
+			for i, domain := range preview.Placement {
+				if used[i] || domain.AvailableDevices != nil && domain.NewOSDs >= *domain.AvailableDevices {
+					continue
+				}
+				if next == -1 || domain.CurrentOSDs+domain.NewOSDs < preview.Placement[next].CurrentOSDs+preview.Placement[next].NewOSDs {
+					next = i
+				}
+			}
+			if next == -1 {
+				unplaced++
+				continue
+			}
+			used[next] = true
+			preview.Placement[next].NewOSDs++
+		}
+	}
+
+	return unplaced, nil
+}
+
+// failureDomainLabel returns the node label of the failure domain, or an empty string if it is unknown
+func failureDomainLabel(storageCluster *ocsv1.StorageCluster, failureDomain string) string {
+	if failureDomain == storageCluster.Status.FailureDomain && storageCluster.Status.FailureDomainKey != "" {
+		return storageCluster.Status.FailureDomainKey
+	}
+	if failureDomain == "host" {
+		return corev1.LabelHostname
+	}
+	if storageCluster.Status.NodeTopologies != nil {
+		key, _ := storageCluster.Status.NodeTopologies.GetKeyValues(failureDomain)
+		return key
+	}
+	return ""
+}
+
+// countAvailableDevices returns the number of devices of each node available for new OSDs. The available PVs of the
+// StorageClass are counted, and on the nodes without PVs of the StorageClass, the devices discovered by the
+// devicefinder.
+func countAvailableDevices(ctx context.Context, cl client.Client, namespace, storageClassName string, size resource.Quantity, hostnames map[string]string) (map[string]int, error) {
+
+	pvList := &corev1.PersistentVolumeList{}
+	if err := cl.List(ctx, pvList); err != nil {
+		return nil, fmt.Errorf("failed to list persistentVolumes: %w", err)
+	}
+
+	devices := map[string]int{}
+	nodesWithVolumes := map[string]bool{}
+	for i := range pvList.Items {
+		pv := &pvList.Items[i]
+		if pv.Spec.StorageClassName != storageClassName {
+			continue
+		}
+		nodeName, ok := hostnames[volumeHostname(pv)]
+		if !ok {
+			continue
+		}
+		nodesWithVolumes[nodeName] = true
+		if isAvailableVolume(pv, storageClassName, size) {
+			devices[nodeName]++
+		}
+	}
+
+	configMapList := &corev1.ConfigMapList{}
+	if err := cl.List(ctx, configMapList, client.InNamespace(namespace), client.MatchingLabels{"app": "devicefinder"}); err != nil {
+		return nil, fmt.Errorf("failed to list device configmaps: %w", err)
+	}
+	for _, configMap := range configMapList.Items {
+		nodeName, ok := strings.CutPrefix(configMap.Name, discoveredDevicesPrefix)
+		if !ok || nodesWithVolumes[nodeName] {
+			continue
+		}
+		var discoveredDevices []apiv1.DiscoveredDevice
+		if err := json.Unmarshal([]byte(configMap.Data["discovered-devices"]), &discoveredDevices); err != nil {
+			klog.Errorf("failed to unmarshal discovered devices for node %s: %v", nodeName, err)
+			continue
+		}
+		for _, device := range discoveredDevices {
+			if device.Size >= size.Value() {
+				devices[nodeName]++
+			}
+		}
+	}
+
+	return devices, nil
+}
+
+// volumeHostname returns the hostname of the node of a local PV
+func volumeHostname(pv *corev1.PersistentVolume) string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == corev1.LabelHostname && expression.Operator == corev1.NodeSelectorOpIn && len(expression.Values) > 0 {
+				return expression.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// placementWarnings adds the warnings about the placement to the preview. It returns the placement efficiency of the
+// OSDs of the device class before and after the expansion.
+func placementWarnings(preview *apiv1.ExpandStoragePreviewResponse, deviceClass string, chunks, unplaced int, size resource.Quantity) (float64, float64) {
+
+	if unplaced > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d of the %d new OSDs cannot be placed, there are not enough available devices of at least %s",
+			unplaced, preview.NewOSDs, size.String()))
+	}
+
+	var current, projected []int
+	for _, domain := range preview.Placement {
+		current = append(current, domain.CurrentOSDs)
+		projected = append(projected, domain.CurrentOSDs+domain.NewOSDs)
+	}
+
+	if domains := len(slices.DeleteFunc(slices.Clone(projected), func(osds int) bool { return osds == 0 })); domains < chunks {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("the pool requires %d %q failure domains with OSDs of device class %q, there are %d",
+			chunks, preview.FailureDomain, deviceClass, domains))
+	}
+
+	projectedEfficiency := placementEfficiency(projected, chunks)
+	if projectedEfficiency > 0 && projectedEfficiency < 1 {
+		most := slices.MaxFunc(preview.Placement, func(a, b apiv1.FailureDomainPlacement) int {
+			return (a.CurrentOSDs + a.NewOSDs) - (b.CurrentOSDs + b.NewOSDs)
+		})
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("the OSDs of device class %q are not balanced across the %q failure domains, %q has %d OSDs, only %d%% of the raw capacity can be used",
+			deviceClass, preview.FailureDomain, most.Name, most.CurrentOSDs+most.NewOSDs, int(projectedEfficiency*100)))
+	}
+
+	return placementEfficiency(current, chunks), projectedEfficiency
+}
+
+// placementEfficiency returns the ratio of the capacity of the OSDs which can be used by a pool storing each object in
+// chunks different failure domains, assuming OSDs of the same size. A failure domain cannot store more than one chunk
+// of an object, so the capacity of the failure domains with more OSDs than the others cannot be fully used.
+func placementEfficiency(osds []int, chunks int) float64 {
+
+	sorted := slices.Sorted(slices.Values(osds))
+	slices.Reverse(sorted)
+	for len(sorted) < chunks {
+		sorted = append(sorted, 0)
+	}
+
+	total := 0
+	for _, count := range sorted {
+		total += count
+	}
+	if total == 0 {
+		return 1
+	}
+
+	// the largest share of the data a failure domain can store is limited by the failure domains with fewer OSDs
+	capacity := float64(total) / float64(chunks)
+	share, rest := capacity, total
+	for j := range chunks {
+		share = min(share, float64(rest)/float64(chunks-j))
+		rest -= sorted[j]
+	}
+
+	return share / capacity
+}
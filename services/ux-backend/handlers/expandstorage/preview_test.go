@@ -0,0 +1,131 @@
+package expandstorage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ocsv1 "github.com/red-hat-storage/ocs-operator/api/v4/v1"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPlacementEfficiency(t *testing.T) {
+	testCases := []struct {
+		osds       []int
+		chunks     int
+		efficiency float64
+	}{
+		{[]int{3, 3, 3}, 3, 1},
+		{[]int{2, 2, 1}, 3, 0.6},
+		{[]int{6, 3, 3}, 3, 0.75},
+		{[]int{3, 3}, 3, 0},
+		{[]int{2, 1, 1, 1, 1}, 3, 1},
+		{[]int{}, 3, 1},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(t, tc.efficiency, placementEfficiency(tc.osds, tc.chunks), 0.001, "osds %v", tc.osds)
+	}
+}
+
+func TestNewPreview(t *testing.T) {
+	ctx := context.Background()
+
+	var objects []client.Object
+	for _, zone := range []string{"a", "b", "c"} {
+		node := &corev1.Node{}
+		node.Name = "node-" + zone
+		node.Labels = map[string]string{
+			storageNodeLabel:         "",
+			corev1.LabelHostname:     node.Name,
+			corev1.LabelTopologyZone: zone,
+		}
+		osd := &appsv1.Deployment{}
+		osd.Name = "rook-ceph-osd-" + zone
+		osd.Namespace = testNamespace
+		osd.Labels = map[string]string{"app": "rook-ceph-osd", "device-class": "ssd2", "topology-location-zone": zone}
+		objects = append(objects, node, osd)
+	}
+	for i, nodeName := range []string{"node-a", "node-a", "node-b"} {
+		pv := &corev1.PersistentVolume{}
+		pv.Name = fmt.Sprintf("local-pv-%d", i)
+		pv.Spec.StorageClassName = "localblock"
+		pv.Spec.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("512Gi")}
+		pv.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{nodeName}},
+			}}},
+		}}
+		pv.Status.Phase = corev1.VolumeAvailable
+		objects = append(objects, pv)
+	}
+	cl := newTestClientBuilder().WithStatusSubresource(&ocsv1.StorageCluster{}).WithObjects(objects...).Build()
+
+	storageCluster := &ocsv1.StorageCluster{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "ocs-storagecluster", Namespace: testNamespace}, storageCluster))
+	storageCluster.Spec.StorageDeviceSets = []ocsv1.StorageDeviceSet{
+		newStorageDeviceSet("localblock", "ssd2", false, resource.MustParse("512Gi"), 1, 3),
+	}
+	assert.NoError(t, cl.Update(ctx, storageCluster))
+	storageCluster.Status.FailureDomain = "zone"
+	storageCluster.Status.FailureDomainKey = corev1.LabelTopologyZone
+	assert.NoError(t, cl.Status().Update(ctx, storageCluster))
+
+	localBlock := &storagev1.StorageClass{}
+	assert.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "localblock"}, localBlock))
+	localBlock.Provisioner = noProvisioner
+	assert.NoError(t, cl.Update(ctx, localBlock))
+
+	// the pool uses the failure domain of the storageCluster
+	req := newTestRequest()
+	req.PoolDetails.FailureDomain = ""
+
+	// the OSD of zone c cannot be placed, node-c has no device
+	preview, err := newPreview(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.DeviceSetScaled, preview.Action)
+	assert.Equal(t, "ssd2", preview.DeviceSetName)
+	assert.Equal(t, "3Ti", preview.RawCapacity.String())
+	assert.Equal(t, "1536Gi", preview.AddedRawCapacity.String())
+	assert.Equal(t, []apiv1.FailureDomainPlacement{
+		{Name: "a", CurrentOSDs: 1, NewOSDs: 1, AvailableDevices: ptr.To(2)},
+		{Name: "b", CurrentOSDs: 1, NewOSDs: 1, AvailableDevices: ptr.To(1)},
+		{Name: "c", CurrentOSDs: 1, NewOSDs: 0, AvailableDevices: ptr.To(0)},
+	}, preview.Placement)
+	// only 3 of the 5 OSDs can be used by a pool of 3 replicas
+	assert.InDelta(t, 0.6*(1<<40), float64(preview.UsableCapacity.Value()), 1)
+	assert.Len(t, preview.Warnings, 2)
+
+	// the devices discovered on node-c are available
+	discovered := &corev1.ConfigMap{}
+	discovered.Name = discoveredDevicesPrefix + "node-c"
+	discovered.Namespace = testNamespace
+	discovered.Labels = map[string]string{"app": "devicefinder"}
+	discovered.Data = map[string]string{"discovered-devices": `[{"path":"/dev/sdb","size":1099511627776},{"path":"/dev/sdc","size":1073741824}]`}
+	assert.NoError(t, cl.Create(ctx, discovered))
+
+	preview, err = newPreview(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, preview.Placement[2].NewOSDs)
+	assert.Equal(t, "1Ti", preview.UsableCapacity.String())
+	assert.Equal(t, "512Gi", preview.AddedUsableCapacity.String())
+	assert.Empty(t, preview.Warnings)
+
+	// an erasure coded pool of 2+2 chunks cannot be placed in 3 zones
+	req.DeviceClass = "nvme"
+	req.PoolDetails.PoolName = "nvme-pool"
+	req.PoolDetails.ErasureCoded = &apiv1.ErasureCodedDetails{DataChunks: 2, CodingChunks: 2}
+	preview, err = newPreview(ctx, cl, testNamespace, req)
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.DeviceSetCreated, preview.Action)
+	assert.Equal(t, "0", preview.UsableCapacity.String())
+	assert.Contains(t, preview.Warnings, `the pool requires 4 "zone" failure domains with OSDs of device class "nvme", there are 3`)
+}
@@ -4,11 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"html"
 	"net/http"
 	"strings"
 
-	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
 
 	nbv1a1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
@@ -37,7 +36,7 @@ func handleGet(w http.ResponseWriter, r *http.Request, client ctrlclient.Client,
 	flagsQuery := r.URL.Query().Get("flags")
 	if flagsQuery == "" {
 		klog.Errorf("flags parameter is required")
-		http.Error(w, "flags parameter is required", http.StatusBadRequest)
+		util.HTTPError(w, r, "flags parameter is required", http.StatusBadRequest)
 		return
 	}
 
@@ -46,32 +45,27 @@ func handleGet(w http.ResponseWriter, r *http.Request, client ctrlclient.Client,
 		flagNames[i] = strings.TrimSpace(flag)
 	}
 
-	type flagResult struct {
-		Value bool   `json:"value"`
-		Error string `json:"error,omitempty"`
-	}
-
-	result := make(map[string]flagResult)
+	result := make(apiv1.FeatureFlagsResponse)
 	for _, flagName := range flagNames {
 		switch flagName {
 		case flagNoobaa:
 			value, err := checkNoobaaFlag(r.Context(), client, namespace)
 			if err != nil {
 				klog.Errorf("failed to check noobaa flag: %v", err)
-				result[flagName] = flagResult{Value: false, Error: err.Error()}
+				result[flagName] = apiv1.FeatureFlag{Value: false, Error: err.Error()}
 			} else {
-				result[flagName] = flagResult{Value: value}
+				result[flagName] = apiv1.FeatureFlag{Value: value}
 			}
 		case flagRGW:
 			value, err := checkRGWFlag(r.Context(), client, namespace)
 			if err != nil {
 				klog.Errorf("failed to check rgw flag: %v", err)
-				result[flagName] = flagResult{Value: false, Error: err.Error()}
+				result[flagName] = apiv1.FeatureFlag{Value: false, Error: err.Error()}
 			} else {
-				result[flagName] = flagResult{Value: value}
+				result[flagName] = apiv1.FeatureFlag{Value: value}
 			}
 		default:
-			result[flagName] = flagResult{Value: false, Error: "unsupported flag"}
+			result[flagName] = apiv1.FeatureFlag{Value: false, Error: "unsupported flag"}
 		}
 	}
 
@@ -95,7 +89,7 @@ func handleGet(w http.ResponseWriter, r *http.Request, client ctrlclient.Client,
 
 	if err := json.NewEncoder(w).Encode(result); err != nil {
 		klog.Errorf("failed to encode response: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		util.HTTPError(w, r, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 }
@@ -160,12 +154,5 @@ func checkRGWFlag(ctx context.Context, client ctrlclient.Client, namespace strin
 }
 
 func handleUnsupportedMethod(w http.ResponseWriter, r *http.Request) {
-	klog.Infof("Only GET method should be used for this endpoint %s", r.URL.Path)
-	w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
-	w.Header().Set("Allow", "GET")
-	w.WriteHeader(http.StatusMethodNotAllowed)
-
-	if _, err := fmt.Fprintf(w, "Unsupported method : %s", html.EscapeString(r.Method)); err != nil {
-		klog.Errorf("failed to write data to response writer: %v", err)
-	}
+	util.HTTPErrorUnsupportedMethod(w, r, "GET")
 }
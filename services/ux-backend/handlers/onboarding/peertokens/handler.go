@@ -1,13 +1,12 @@
 package peertokens
 
 import (
-	"fmt"
-	"html"
 	"net/http"
 
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -27,24 +26,25 @@ func HandleMessage(w http.ResponseWriter, r *http.Request, tokenLifetimeInHours
 
 func handlePost(w http.ResponseWriter, r *http.Request, tokenLifetimeInHours int, cl client.Client, namespace string) {
 
+	// the token is signed with the onboarding private key, only the users who can read the key can get a token
+	if !util.AuthorizeRequest(w, r, cl, authorizationv1.ResourceAttributes{
+		Resource: "secrets", Namespace: namespace, Name: "onboarding-private-key", Verb: "get"}) {
+		return
+	}
+
 	storageCluster, err := util.GetStorageClusterInNamespace(r.Context(), cl, namespace)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		util.HTTPError(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	if onboardingToken, err := util.GeneratePeerOnboardingToken(tokenLifetimeInHours, onboardingPrivateKeyFilePath, storageCluster.UID); err != nil {
 		klog.Errorf("failed to get onboarding token: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
-
-		if _, err := w.Write([]byte("Failed to generate token")); err != nil {
-			klog.Errorf("failed write data to response writer, %v", err)
-		}
+		util.HTTPError(w, r, "Failed to generate token", http.StatusInternalServerError)
 	} else {
 		klog.Info("onboarding token generated successfully")
-		w.WriteHeader(http.StatusOK)
 		w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
+		w.WriteHeader(http.StatusOK)
 
 		if _, err = w.Write([]byte(onboardingToken)); err != nil {
 			klog.Errorf("failed write data to response writer: %v", err)
@@ -53,12 +53,5 @@ func handlePost(w http.ResponseWriter, r *http.Request, tokenLifetimeInHours int
 }
 
 func handleUnsupportedMethod(w http.ResponseWriter, r *http.Request) {
-	klog.Infof("Only POST method should be used to send data to this endpoint %s", r.URL.Path)
-	w.WriteHeader(http.StatusMethodNotAllowed)
-	w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
-	w.Header().Set("Allow", "POST")
-
-	if _, err := fmt.Fprintf(w, "Unsupported method: %s", html.EscapeString(r.Method)); err != nil {
-		klog.Errorf("failed write data to response writer: %v", err)
-	}
+	util.HTTPErrorUnsupportedMethod(w, r, "POST")
 }
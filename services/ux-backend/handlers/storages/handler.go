@@ -4,11 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"html"
 	"net/http"
 
 	ocsv1 "github.com/red-hat-storage/ocs-operator/api/v4/v1"
-	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/util"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -35,7 +34,7 @@ func handleGet(w http.ResponseWriter, r *http.Request, client ctrlclient.Client,
 	result, err := getStorageInfo(r.Context(), client, namespace)
 	if err != nil {
 		klog.Errorf("failed to get storage info: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to get storage info: %v", err), http.StatusInternalServerError)
+		util.HTTPError(w, r, fmt.Sprintf("Failed to get storage info: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -44,23 +43,12 @@ func handleGet(w http.ResponseWriter, r *http.Request, client ctrlclient.Client,
 
 	if err := json.NewEncoder(w).Encode(result); err != nil {
 		klog.Errorf("failed to encode response: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		util.HTTPError(w, r, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 }
 
-type storageInfoResponse struct {
-	OperatorNamespace string                                 `json:"operatorNamespace"`
-	ClusterNamespaces map[string]clusterNamespaceStorageInfo `json:"clusterNamespaces"`
-}
-
-type clusterNamespaceStorageInfo struct {
-	StorageClusterName string `json:"storageClusterName"`
-	DeploymentType     string `json:"deploymentType"`
-	RgwSecureEndpoint  string `json:"rgwSecureEndpoint,omitempty"`
-}
-
-func getStorageInfo(ctx context.Context, client ctrlclient.Client, namespace string) (*storageInfoResponse, error) {
+func getStorageInfo(ctx context.Context, client ctrlclient.Client, namespace string) (*apiv1.StoragesResponse, error) {
 	klog.Info("Getting storage info")
 
 	storageClusterList := &ocsv1.StorageClusterList{}
@@ -82,9 +70,9 @@ func getStorageInfo(ctx context.Context, client ctrlclient.Client, namespace str
 		}
 	}
 
-	result := &storageInfoResponse{
+	result := &apiv1.StoragesResponse{
 		OperatorNamespace: namespace,
-		ClusterNamespaces: make(map[string]clusterNamespaceStorageInfo),
+		ClusterNamespaces: make(map[string]apiv1.ClusterNamespaceStorage),
 	}
 
 	for _, sc := range storageClusterList.Items {
@@ -93,7 +81,7 @@ func getStorageInfo(ctx context.Context, client ctrlclient.Client, namespace str
 		}
 
 		scNamespace := sc.Namespace
-		namespaceInfo := clusterNamespaceStorageInfo{
+		namespaceInfo := apiv1.ClusterNamespaceStorage{
 			StorageClusterName: sc.Name,
 			DeploymentType:     getDeploymentType(&sc),
 		}
@@ -137,12 +125,5 @@ func getRgwSecureEndpoint(cos *cephv1.CephObjectStore) string {
 }
 
 func handleUnsupportedMethod(w http.ResponseWriter, r *http.Request) {
-	klog.Infof("Only GET method should be used for this endpoint %s", r.URL.Path)
-	w.Header().Set("Content-Type", handlers.ContentTypeTextPlain)
-	w.Header().Set("Allow", "GET")
-	w.WriteHeader(http.StatusMethodNotAllowed)
-
-	if _, err := fmt.Fprintf(w, "Unsupported method : %s", html.EscapeString(r.Method)); err != nil {
-		klog.Errorf("failed to write data to response writer: %v", err)
-	}
+	util.HTTPErrorUnsupportedMethod(w, r, "GET")
 }
@@ -2,14 +2,18 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	ocsv1 "github.com/red-hat-storage/ocs-operator/api/v4/v1"
+	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers/bucket"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers/cnsa/devicefinder"
 	"github.com/red-hat-storage/odf-operator/services/ux-backend/handlers/cnsa/registrychecks"
@@ -23,6 +27,8 @@ import (
 	nbv1a1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,6 +43,7 @@ type serverConfig struct {
 	listenPort           int
 	tokenLifetimeInHours int
 	tlsEnabled           bool
+	tlsConfig            *tls.Config
 }
 
 func loadAndValidateServerConfig() (*serverConfig, error) {
@@ -58,9 +65,41 @@ func loadAndValidateServerConfig() (*serverConfig, error) {
 		return nil, err
 	}
 
+	config.tlsConfig, err = loadTLSProfileConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// loadTLSProfileConfig returns the TLS config of the TLSProfile passed by odf-operator,
+// or nil if the server has to keep the Go defaults
+func loadTLSProfileConfig() (*tls.Config, error) {
+	version := os.Getenv("TLS_PROFILE_VERSION")
+	if version == "" {
+		return nil, nil
+	}
+
+	cfg := &ocstlsv1.TLSConfig{Version: ocstlsv1.TLSProtocolVersion(version)}
+	for _, cipher := range strings.Split(os.Getenv("TLS_PROFILE_CIPHERS"), ",") {
+		if cipher != "" {
+			cfg.Ciphers = append(cfg.Ciphers, ocstlsv1.TLSCipherSuite(cipher))
+		}
+	}
+	for _, group := range strings.Split(os.Getenv("TLS_PROFILE_GROUPS"), ",") {
+		if group != "" {
+			cfg.Groups = append(cfg.Groups, ocstlsv1.TLSGroupName(group))
+		}
+	}
+
+	if err := ocstlsv1.ValidateTLSConfig(cfg); err != nil {
+		return nil, fmt.Errorf("malformed user-defined TLS profile config: %v", err)
+	}
+
+	return ocstlsv1.GetGoTLSConfig(cfg), nil
+}
+
 func main() {
 	klog.Info("Starting ux backend server")
 
@@ -84,6 +123,12 @@ func main() {
 	if err := appsv1.AddToScheme(scheme); err != nil {
 		klog.Exitf("failed to add appsv1 to scheme. %v", err)
 	}
+	if err := authenticationv1.AddToScheme(scheme); err != nil {
+		klog.Exitf("failed to add authenticationv1 to scheme. %v", err)
+	}
+	if err := authorizationv1.AddToScheme(scheme); err != nil {
+		klog.Exitf("failed to add authorizationv1 to scheme. %v", err)
+	}
 	if err := ocsv1.AddToScheme(scheme); err != nil {
 		klog.Exitf("failed to add ocsv1 to scheme. %v", err)
 	}
@@ -153,36 +198,59 @@ func main() {
 		panic("cache did not sync")
 	}
 
-	// Authenticated + Authorized endpoints (require both authentication and authorization)
+	// The routes are served under the versioned prefix of the API, described by its OpenAPI document. The
+	// unversioned routes are kept for the clients written against them.
+	handle := func(path string, handler http.HandlerFunc) {
+		http.HandleFunc(path, handler)
+		http.HandleFunc(apiv1.PathPrefix+path, handler)
+	}
+
+	// Authenticated + Authorized endpoints (require both authentication and authorization), the handlers check with
+	// SubjectAccessReviews that the user is allowed to perform the actions made on its behalf
 
-	http.HandleFunc("/onboarding/peer-tokens", func(w http.ResponseWriter, r *http.Request) {
+	handle("/onboarding/peer-tokens", func(w http.ResponseWriter, r *http.Request) {
 		peertokens.HandleMessage(w, r, config.tokenLifetimeInHours, cl, namespace)
 	})
 
-	http.HandleFunc("/expandstorage", func(w http.ResponseWriter, r *http.Request) {
+	handle("/expandstorage", func(w http.ResponseWriter, r *http.Request) {
 		expandstorage.HandleMessage(w, r, cl, namespace)
 	})
 
+	handle("/expandstorage/preview", func(w http.ResponseWriter, r *http.Request) {
+		expandstorage.HandlePreviewMessage(w, r, cl, namespace)
+	})
+
 	// Authenticated endpoints (require authentication but not authorization)
 
-	http.HandleFunc("/info/featureflags", func(w http.ResponseWriter, r *http.Request) {
+	handle("/info/featureflags", func(w http.ResponseWriter, r *http.Request) {
 		featureflags.HandleMessage(w, r, cl, namespace)
 	})
 
-	http.HandleFunc("/info/bucket/{name}", func(w http.ResponseWriter, r *http.Request) {
+	handle("/info/bucket/{name}", func(w http.ResponseWriter, r *http.Request) {
 		bucket.HandleMessage(w, r, cl)
 	})
 
-	http.HandleFunc("/info/storages", func(w http.ResponseWriter, r *http.Request) {
+	handle("/info/storages", func(w http.ResponseWriter, r *http.Request) {
 		storages.HandleMessage(w, r, cl, namespace)
 	})
 
+	http.HandleFunc(apiv1.OpenAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			util.HTTPErrorUnsupportedMethod(w, r, http.MethodGet)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		if _, err := w.Write(apiv1.OpenAPIDocument); err != nil {
+			klog.Errorf("failed to write data to response writer: %v", err)
+		}
+	})
+
 	// CNSA endpoints
-	http.HandleFunc("/cnsa/devicefinder", func(w http.ResponseWriter, r *http.Request) {
+	handle("/cnsa/devicefinder", func(w http.ResponseWriter, r *http.Request) {
 		devicefinder.HandleMessage(w, r, cl, namespace)
 	})
 
-	http.HandleFunc("/cnsa/registry-checks", func(w http.ResponseWriter, r *http.Request) {
+	handle("/cnsa/registry-checks", func(w http.ResponseWriter, r *http.Request) {
 		registrychecks.HandleMessage(w, r, client)
 	})
 
@@ -195,10 +263,14 @@ func main() {
 		WriteTimeout:      15 * time.Second,
 		IdleTimeout:       60 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
+		TLSConfig:         config.tlsConfig,
 	}
 
 	if config.tlsEnabled {
 		klog.Info("Server configured to run with TLS")
+		if config.tlsConfig != nil {
+			klog.Info("Server configured with the TLS config of the TLSProfile")
+		}
 		err = server.ListenAndServeTLS(
 			"/etc/tls/private/tls.crt",
 			"/etc/tls/private/tls.key",
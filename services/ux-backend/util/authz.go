@@ -0,0 +1,129 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ForwardedAccessTokenHeader is the header in which oauth-proxy passes the access token of the user
+	ForwardedAccessTokenHeader = "X-Forwarded-Access-Token"
+	// AuthorizationHeader carries the bearer token of the clients which pass their own token
+	AuthorizationHeader = "Authorization"
+)
+
+var (
+	// ErrUnauthenticated is returned when the request carries no valid access token
+	ErrUnauthenticated = errors.New("the request is not authenticated")
+	// ErrForbidden is returned when the user of the request is not allowed to perform an action
+	ErrForbidden = errors.New("the request is forbidden")
+)
+
+// GetRequestUser returns the user of the request. The access token forwarded by oauth-proxy is reviewed with the
+// API server, so a request sent around the proxy cannot claim another identity. Without a forwarded token, the bearer
+// token of the Authorization header is reviewed.
+func GetRequestUser(ctx context.Context, cl client.Client, r *http.Request) (*authenticationv1.UserInfo, error) {
+	token := r.Header.Get(ForwardedAccessTokenHeader)
+	if token == "" {
+		token = getBearerToken(r)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("%w: no %s or bearer %s header", ErrUnauthenticated, ForwardedAccessTokenHeader, AuthorizationHeader)
+	}
+
+	tokenReview := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if err := cl.Create(ctx, tokenReview); err != nil {
+		return nil, fmt.Errorf("failed to review the access token: %v", err)
+	}
+	if !tokenReview.Status.Authenticated {
+		return nil, fmt.Errorf("%w: %s", ErrUnauthenticated, tokenReview.Status.Error)
+	}
+
+	return &tokenReview.Status.User, nil
+}
+
+// Authorize checks with a SubjectAccessReview per action that the user is allowed to perform all the actions
+func Authorize(ctx context.Context, cl client.Client, user *authenticationv1.UserInfo, actions ...authorizationv1.ResourceAttributes) error {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	for i := range actions {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &actions[i],
+				User:               user.Username,
+				UID:                user.UID,
+				Groups:             user.Groups,
+				Extra:              extra,
+			},
+		}
+		if err := cl.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to review the access of user %q: %v", user.Username, err)
+		}
+		if !review.Status.Allowed {
+			return fmt.Errorf("%w: user %q cannot %s", ErrForbidden, user.Username, describeAction(&actions[i]))
+		}
+	}
+
+	return nil
+}
+
+// AuthorizeRequest checks that the user of the request is allowed to perform all the actions. Otherwise the error is
+// written to the response and false is returned, the handler must not proceed in that case.
+func AuthorizeRequest(w http.ResponseWriter, r *http.Request, cl client.Client, actions ...authorizationv1.ResourceAttributes) bool {
+	user, err := GetRequestUser(r.Context(), cl, r)
+	if err == nil {
+		err = Authorize(r.Context(), cl, user, actions...)
+	}
+
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrUnauthenticated):
+		klog.Errorf("rejecting request to %s: %v", r.URL.Path, err)
+		HTTPError(w, r, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		klog.Errorf("rejecting request to %s: %v", r.URL.Path, err)
+		HTTPError(w, r, err.Error(), http.StatusForbidden)
+	default:
+		klog.Errorf("failed to authorize request to %s: %v", r.URL.Path, err)
+		HTTPError(w, r, err.Error(), http.StatusInternalServerError)
+	}
+
+	return false
+}
+
+// getBearerToken returns the token of the bearer Authorization header of the request, if any
+func getBearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get(AuthorizationHeader), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func describeAction(action *authorizationv1.ResourceAttributes) string {
+	resource := action.Resource
+	if action.Group != "" {
+		resource = strings.Join([]string{action.Resource, action.Group}, ".")
+	}
+	if action.Name != "" {
+		resource = fmt.Sprintf("%s %q", resource, action.Name)
+	}
+	if action.Namespace != "" {
+		return fmt.Sprintf("%s %s in namespace %q", action.Verb, resource, action.Namespace)
+	}
+	return fmt.Sprintf("%s %s", action.Verb, resource)
+}
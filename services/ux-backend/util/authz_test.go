@@ -0,0 +1,187 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	testToken          = "admin-token"
+	testForbiddenToken = "developer-token"
+)
+
+// testUsers are the users authenticated by the test client, by token
+var testUsers = map[string]string{
+	testToken:          "admin",
+	testForbiddenToken: "developer",
+}
+
+var testAction = authorizationv1.ResourceAttributes{
+	Verb:      "update",
+	Group:     "ocs.openshift.io",
+	Resource:  "storageclusters",
+	Name:      "ocs-storagecluster",
+	Namespace: "openshift-storage",
+}
+
+// newTestClient returns a client which authenticates the testUsers, and only allows the admin user to perform the
+// actions. A non-nil reviewErr fails the reviews.
+func newTestClient(reviewErr error) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(authenticationv1.AddToScheme(scheme))
+	utilruntime.Must(authorizationv1.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if reviewErr != nil {
+				return reviewErr
+			}
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				if username, ok := testUsers[review.Spec.Token]; ok {
+					review.Status.Authenticated = true
+					review.Status.User = authenticationv1.UserInfo{Username: username, Groups: []string{"system:authenticated"}}
+				} else {
+					review.Status.Error = "invalid token"
+				}
+			case *authorizationv1.SubjectAccessReview:
+				review.Status.Allowed = review.Spec.User == "admin"
+			}
+			return nil
+		},
+	}).Build()
+}
+
+func TestGetRequestUser(t *testing.T) {
+	testCases := []struct {
+		label   string
+		headers map[string]string
+		wantErr error
+	}{
+		{
+			label:   "forwarded access token",
+			headers: map[string]string{ForwardedAccessTokenHeader: testToken},
+		},
+		{
+			label:   "bearer token",
+			headers: map[string]string{AuthorizationHeader: "Bearer " + testToken},
+		},
+		{
+			label:   "forwarded access token takes precedence over the bearer token",
+			headers: map[string]string{ForwardedAccessTokenHeader: testToken, AuthorizationHeader: "Bearer other-token"},
+		},
+		{
+			label:   "no token",
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			label:   "basic authorization",
+			headers: map[string]string{AuthorizationHeader: "Basic YWRtaW46YWRtaW4="},
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			label:   "invalid token",
+			headers: map[string]string{AuthorizationHeader: "Bearer other-token"},
+			wantErr: ErrUnauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/expandstorage", nil)
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+
+			user, err := GetRequestUser(context.Background(), newTestClient(nil), r)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, user)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.NotNil(t, user) {
+				assert.Equal(t, "admin", user.Username)
+			}
+		})
+	}
+
+	// a failed review is not reported as unauthenticated
+	r := httptest.NewRequest(http.MethodPost, "/v1/expandstorage", nil)
+	r.Header.Set(ForwardedAccessTokenHeader, testToken)
+	_, err := GetRequestUser(context.Background(), newTestClient(errors.New("unavailable")), r)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := context.Background()
+	admin := &authenticationv1.UserInfo{Username: "admin"}
+	developer := &authenticationv1.UserInfo{Username: "developer"}
+
+	assert.NoError(t, Authorize(ctx, newTestClient(nil), admin, testAction, testAction))
+
+	err := Authorize(ctx, newTestClient(nil), developer, testAction)
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.Contains(t, err.Error(), `update storageclusters.ocs.openshift.io "ocs-storagecluster" in namespace "openshift-storage"`)
+
+	err = Authorize(ctx, newTestClient(errors.New("unavailable")), admin, testAction)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrForbidden)
+}
+
+func TestAuthorizeRequest(t *testing.T) {
+	testCases := []struct {
+		label     string
+		token     string
+		reviewErr error
+		wantCode  int
+	}{
+		{
+			label:    "unauthenticated",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			label:    "forbidden",
+			token:    testForbiddenToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			label:    "allowed",
+			token:    testToken,
+			wantCode: http.StatusOK,
+		},
+		{
+			label:     "review error",
+			token:     testToken,
+			reviewErr: errors.New("unavailable"),
+			wantCode:  http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/expandstorage", nil)
+			if tc.token != "" {
+				r.Header.Set(AuthorizationHeader, "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+
+			allowed := AuthorizeRequest(w, r, newTestClient(tc.reviewErr), testAction)
+			assert.Equal(t, tc.wantCode == http.StatusOK, allowed)
+			assert.Equal(t, tc.wantCode, w.Code)
+		})
+	}
+}
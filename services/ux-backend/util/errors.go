@@ -0,0 +1,37 @@
+package util
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"strings"
+
+	apiv1 "github.com/red-hat-storage/odf-operator/services/ux-backend/api/v1"
+
+	"k8s.io/klog/v2"
+)
+
+// HTTPError replies to the request with the error. The v1 API replies with the JSON error envelope, the unversioned
+// routes keep replying with plain text for the clients written against them.
+func HTTPError(w http.ResponseWriter, r *http.Request, message string, code int) {
+	if !strings.HasPrefix(r.URL.Path, apiv1.PathPrefix+"/") {
+		http.Error(w, message, code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+
+	response := apiv1.ErrorResponse{Error: apiv1.ErrorDetails{Code: code, Message: message}}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		klog.Errorf("failed to encode error response: %v", err)
+	}
+}
+
+// HTTPErrorUnsupportedMethod replies to a request with a method which is not allowed on the path
+func HTTPErrorUnsupportedMethod(w http.ResponseWriter, r *http.Request, allowed ...string) {
+	klog.Infof("Only %s method should be used for this endpoint %s", strings.Join(allowed, ", "), r.URL.Path)
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	HTTPError(w, r, "Unsupported method: "+html.EscapeString(r.Method), http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// CsvAdmissionAuditPath is the path on the metrics server where the audit records are served
+	CsvAdmissionAuditPath = "/debug/csv-webhook-audit"
+)
+
+// CsvAdmissionAuditRecord describes an admission review of a csv managed by ODF
+type CsvAdmissionAuditRecord struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Namespace string    `json:"namespace"`
+	CsvName   string    `json:"csvName"`
+	Outcome   string    `json:"outcome"`
+	Message   string    `json:"message,omitempty"`
+	// Replicas of the csv deployments as admitted, nil replicas are omitted
+	Replicas map[string]int32 `json:"replicas,omitempty"`
+}
+
+// CsvAdmissionAudit keeps the most recent admission records in a bounded ring.
+// A nil *CsvAdmissionAudit is valid and records nothing.
+type CsvAdmissionAudit struct {
+	mutex   sync.Mutex
+	records []CsvAdmissionAuditRecord
+	next    int
+	full    bool
+}
+
+// NewCsvAdmissionAudit returns an audit ring holding up to size records, or nil if size is not positive.
+func NewCsvAdmissionAudit(size int) *CsvAdmissionAudit {
+
+	if size <= 0 {
+		return nil
+	}
+
+	return &CsvAdmissionAudit{records: make([]CsvAdmissionAuditRecord, size)}
+}
+
+// Record adds the record to the ring, overwriting the oldest one when it is full.
+func (a *CsvAdmissionAudit) Record(record CsvAdmissionAuditRecord) {
+
+	if a == nil {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.records[a.next] = record
+	a.next = (a.next + 1) % len(a.records)
+	if a.next == 0 {
+		a.full = true
+	}
+}
+
+// Records returns the recorded admissions, oldest first.
+func (a *CsvAdmissionAudit) Records() []CsvAdmissionAuditRecord {
+
+	if a == nil {
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if !a.full {
+		return append([]CsvAdmissionAuditRecord{}, a.records[:a.next]...)
+	}
+
+	return append(append([]CsvAdmissionAuditRecord{}, a.records[a.next:]...), a.records[:a.next]...)
+}
+
+// ServeHTTP serves the recorded admissions as a json list, oldest first.
+func (a *CsvAdmissionAudit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	records := a.Records()
+	if records == nil {
+		records = []CsvAdmissionAuditRecord{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAuditRecord(i int) CsvAdmissionAuditRecord {
+	return CsvAdmissionAuditRecord{
+		Time:      time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+		Operation: "UPDATE",
+		Namespace: "openshift-storage",
+		CsvName:   fmt.Sprintf("csv-%d", i),
+		Outcome:   admissionOutcomeAllowed,
+	}
+}
+
+func auditCsvNames(records []CsvAdmissionAuditRecord) []string {
+	names := []string{}
+	for _, record := range records {
+		names = append(names, record.CsvName)
+	}
+	return names
+}
+
+func TestCsvAdmissionAuditOrdering(t *testing.T) {
+	audit := NewCsvAdmissionAudit(3)
+	assert.Empty(t, audit.Records())
+
+	audit.Record(newTestAuditRecord(1))
+	audit.Record(newTestAuditRecord(2))
+	assert.Equal(t, []string{"csv-1", "csv-2"}, auditCsvNames(audit.Records()))
+
+	audit.Record(newTestAuditRecord(3))
+	assert.Equal(t, []string{"csv-1", "csv-2", "csv-3"}, auditCsvNames(audit.Records()))
+}
+
+func TestCsvAdmissionAuditWrapAround(t *testing.T) {
+	audit := NewCsvAdmissionAudit(3)
+
+	for i := 1; i <= 4; i++ {
+		audit.Record(newTestAuditRecord(i))
+	}
+	assert.Equal(t, []string{"csv-2", "csv-3", "csv-4"}, auditCsvNames(audit.Records()))
+
+	for i := 5; i <= 7; i++ {
+		audit.Record(newTestAuditRecord(i))
+	}
+	assert.Equal(t, []string{"csv-5", "csv-6", "csv-7"}, auditCsvNames(audit.Records()))
+
+	// the returned records are a copy, later records do not change them
+	records := audit.Records()
+	audit.Record(newTestAuditRecord(8))
+	assert.Equal(t, []string{"csv-5", "csv-6", "csv-7"}, auditCsvNames(records))
+}
+
+func TestCsvAdmissionAuditDisabled(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		audit := NewCsvAdmissionAudit(size)
+		assert.Nil(t, audit)
+
+		audit.Record(newTestAuditRecord(1))
+		assert.Nil(t, audit.Records())
+	}
+}
+
+func TestCsvAdmissionAuditServeHTTP(t *testing.T) {
+	audit := NewCsvAdmissionAudit(2)
+	record := newTestAuditRecord(1)
+	record.Replicas = map[string]int32{"ocs-operator": 1}
+	audit.Record(record)
+	audit.Record(newTestAuditRecord(2))
+	audit.Record(newTestAuditRecord(3))
+
+	rec := httptest.NewRecorder()
+	audit.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, CsvAdmissionAuditPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var records []CsvAdmissionAuditRecord
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+	assert.Equal(t, []CsvAdmissionAuditRecord{newTestAuditRecord(2), newTestAuditRecord(3)}, records)
+
+	// the disabled audit serves an empty list rather than null
+	var disabled *CsvAdmissionAudit
+	rec = httptest.NewRecorder()
+	disabled.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, CsvAdmissionAuditPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	audit.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, CsvAdmissionAuditPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
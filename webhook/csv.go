@@ -22,9 +22,11 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/red-hat-storage/odf-operator/controllers"
+	"github.com/red-hat-storage/odf-operator/metrics"
 )
 
 type ClusterServiceVersionDeploymentScaler struct {
@@ -40,34 +43,95 @@ type ClusterServiceVersionDeploymentScaler struct {
 
 	Decoder           admission.Decoder
 	OperatorNamespace string
+	// Audit records the recent admissions of the csvs managed by ODF, it is disabled when nil
+	Audit *CsvAdmissionAudit
 
 	odfOperatorConfigAccessMutex        sync.Mutex
 	odfOperatorConfigMapResourceVersion string
 	odfOwnedCsvNames                    map[string]bool
+	odfCsvCarryOverFields               map[string][]string
+	odfCsvPackages                      map[string]string
 }
 
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=get;patch
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get
 
+const (
+	admissionOutcomeAllowedUnmanaged = "allowed_unmanaged"
+	admissionOutcomeAllowed          = "allowed"
+	admissionOutcomeScaledDown       = "scaled_down"
+	admissionOutcomeCarriedOver      = "carried_over"
+	admissionOutcomeRepaired         = "repaired"
+	admissionOutcomeImageOverridden  = "image_overridden"
+	admissionOutcomeAllowedOnError   = "allowed_on_error"
+	admissionOutcomeErrored          = "errored"
+)
+
 func (r *ClusterServiceVersionDeploymentScaler) Handle(ctx context.Context, req admission.Request) admission.Response {
 
 	logger := log.FromContext(ctx)
-	logger.Info("request received for csv review")
+	logger.Info("request received for csv review", "operation", req.Operation)
 
+	startTime := time.Now()
 	csv := &opv1a1.ClusterServiceVersion{}
+	response, outcome := r.review(ctx, logger, req, csv)
+	metrics.ReportCsvWebhookAdmission(string(req.Operation), outcome, time.Since(startTime))
+
+	if outcome != admissionOutcomeAllowedUnmanaged {
+		r.recordAdmission(req, csv, outcome, response)
+	}
+
+	return response
+}
+
+func (r *ClusterServiceVersionDeploymentScaler) review(ctx context.Context, logger logr.Logger, req admission.Request, csv *opv1a1.ClusterServiceVersion) (admission.Response, string) {
+
 	if err := r.Decoder.Decode(req, csv); err != nil {
 		logger.Error(err, "failed decoding admission review as csv")
-		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding admission review as csv: %v", err))
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding admission review as csv: %v", err)), admissionOutcomeErrored
 	}
 
 	if err := r.loadOdfConfigMapData(ctx, logger); err != nil {
-		logger.Error(err, "failed to build config")
-		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to build config: %v", err))
+		return r.internalError(logger, req, "failed to build config", err)
 	}
 
 	if ok := r.isCsvManagedByOdf(csv); !ok {
 		logger.Info("ignoring csv as it is not a csv managed by ODF")
-		return admission.Allowed("csv is not managed by ODF")
+		return admission.Allowed("csv is not managed by ODF"), admissionOutcomeAllowedUnmanaged
+	}
+
+	imageOverrides, err := controllers.GetImageOverrides(ctx, r.Client, logger)
+	if err != nil {
+		return r.internalError(logger, req, "failed to get image overrides", err)
+	}
+
+	states, err := controllers.GetCsvScaleStates(ctx, r.Client)
+	if err != nil {
+		return r.internalError(logger, req, "failed to get csv scale states", err)
+	}
+
+	if state, ok := states[csv.Name]; ok {
+		// the operator scaler has already decided the scale state of this csv
+		// keep it regardless of the replicas requested by the create or update
+		isRepaired := controllers.ApplyCsvScaleState(csv, state)
+		isImageOverridden := r.applyImageOverrides(logger, csv, imageOverrides)
+		if isRepaired {
+			logger.Info("repairing csv replicas to the desired scale state", "state", state)
+			return r.patchResponse(logger, req, csv, admissionOutcomeRepaired)
+		}
+		if isImageOverridden {
+			return r.patchResponse(logger, req, csv, admissionOutcomeImageOverridden)
+		}
+		logger.Info("csv is in line with the desired scale state", "state", state)
+		return admission.Allowed("csv is in line with the desired scale state"), admissionOutcomeAllowed
+	}
+
+	if req.Operation != admissionv1.Create {
+		if r.applyImageOverrides(logger, csv, imageOverrides) {
+			return r.patchResponse(logger, req, csv, admissionOutcomeImageOverridden)
+		}
+		logger.Info("allowing csv update as no scale state is recorded for it")
+		return admission.Allowed("no scale state is recorded for the csv"), admissionOutcomeAllowed
 	}
 
 	var isPrevCsvHasRunningDeployments bool
@@ -77,7 +141,7 @@ func (r *ClusterServiceVersionDeploymentScaler) Handle(ctx context.Context, req
 		if err := r.Client.Get(ctx, key, prevCsv); err != nil {
 			if client.IgnoreNotFound(err) != nil {
 				logger.Error(err, "failed to get previous CSV", "csv", csv.Spec.Replaces)
-				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed getting previous csv %q: %w", csv.Spec.Replaces, err))
+				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed getting previous csv %q: %w", csv.Spec.Replaces, err)), admissionOutcomeErrored
 			}
 		} else {
 			logger.Info("previous CSV found", "csv", csv.Spec.Replaces)
@@ -86,18 +150,84 @@ func (r *ClusterServiceVersionDeploymentScaler) Handle(ctx context.Context, req
 		}
 	}
 
+	r.applyImageOverrides(logger, csv, imageOverrides)
+
 	if !isPrevCsvHasRunningDeployments {
 		logger.Info("scaling down deployments")
 		r.scaleDownCsvDeployments(logger, csv)
+		return r.patchResponse(logger, req, csv, admissionOutcomeScaledDown)
+	}
+
+	return r.patchResponse(logger, req, csv, admissionOutcomeCarriedOver)
+}
+
+// internalError fails the admission of a csv create on an internal error. Updates are allowed
+// instead as the operator scaler repairs the replicas from the recorded scale state on its next
+// reconcile, failing them would block OLM from progressing the csv until the error is gone.
+func (r *ClusterServiceVersionDeploymentScaler) internalError(logger logr.Logger, req admission.Request, msg string, err error) (admission.Response, string) {
+
+	logger.Error(err, msg)
+
+	if req.Operation == admissionv1.Update {
+		warning := fmt.Sprintf("%s, the csv is allowed unchanged: %v", msg, err)
+		return admission.Allowed(msg).WithWarnings(warning), admissionOutcomeAllowedOnError
 	}
 
+	return admission.Errored(http.StatusInternalServerError, fmt.Errorf("%s: %v", msg, err)), admissionOutcomeErrored
+}
+
+func (r *ClusterServiceVersionDeploymentScaler) applyImageOverrides(logger logr.Logger, csv *opv1a1.ClusterServiceVersion, imageOverrides []controllers.ImageOverride) bool {
+
+	r.odfOperatorConfigAccessMutex.Lock()
+	pkg := r.odfCsvPackages[csv.Name]
+	r.odfOperatorConfigAccessMutex.Unlock()
+
+	if !controllers.ApplyImageOverrides(csv, pkg, imageOverrides) {
+		return false
+	}
+
+	logger.Info("applied image overrides to the csv")
+	return true
+}
+
+func (r *ClusterServiceVersionDeploymentScaler) recordAdmission(req admission.Request, csv *opv1a1.ClusterServiceVersion, outcome string, response admission.Response) {
+
+	if r.Audit == nil {
+		return
+	}
+
+	record := CsvAdmissionAuditRecord{
+		Time:      time.Now(),
+		Operation: string(req.Operation),
+		Namespace: req.Namespace,
+		CsvName:   req.Name,
+		Outcome:   outcome,
+		Replicas:  map[string]int32{},
+	}
+	if csv.Name != "" {
+		record.CsvName = csv.Name
+	}
+	if response.Result != nil {
+		record.Message = response.Result.Message
+	}
+	for _, deployment := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		if deployment.Spec.Replicas != nil {
+			record.Replicas[deployment.Name] = *deployment.Spec.Replicas
+		}
+	}
+
+	r.Audit.Record(record)
+}
+
+func (r *ClusterServiceVersionDeploymentScaler) patchResponse(logger logr.Logger, req admission.Request, csv *opv1a1.ClusterServiceVersion, outcome string) (admission.Response, string) {
+
 	marshaledCsv, err := json.Marshal(csv)
 	if err != nil {
 		logger.Error(err, "failed marshaling csv")
-		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed marshaling csv: %v", err))
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed marshaling csv: %v", err)), admissionOutcomeErrored
 	}
 
-	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledCsv)
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledCsv), outcome
 }
 
 func (r *ClusterServiceVersionDeploymentScaler) loadOdfConfigMapData(ctx context.Context, logger logr.Logger) error {
@@ -115,6 +245,8 @@ func (r *ClusterServiceVersionDeploymentScaler) loadOdfConfigMapData(ctx context
 	}
 
 	r.odfOwnedCsvNames = map[string]bool{}
+	r.odfCsvCarryOverFields = map[string][]string{}
+	r.odfCsvPackages = map[string]string{}
 	controllers.ParseOdfConfigMapRecords(logger, configmap, func(record *controllers.OdfOperatorConfigMapRecord, key, rawValue string) {
 		if record.Csv == "" {
 			logger.Info("skipping the record from the configmap", "key", key, "value", rawValue)
@@ -122,17 +254,26 @@ func (r *ClusterServiceVersionDeploymentScaler) loadOdfConfigMapData(ctx context
 		}
 
 		r.odfOwnedCsvNames[record.Csv] = true
+		r.odfCsvPackages[record.Csv] = record.Pkg
+
+		r.odfCsvCarryOverFields[record.Csv] = controllers.GetCsvCarryOverFields(logger, key, record.CarryOverFields)
 	})
 
 	r.odfOperatorConfigMapResourceVersion = configmap.ResourceVersion
-	logger.Info("webhook csv records", "records", r.odfOwnedCsvNames)
+	metrics.ReportCsvWebhookConfigReload()
+	logger.Info("webhook csv records", "records", r.odfOwnedCsvNames, "carryOverFields", r.odfCsvCarryOverFields)
 
 	return nil
 }
 
-// syncNewCsvWithPrevCsv copies the required fields from the previous csv that are required after upgrade in the new csv
+// syncNewCsvWithPrevCsv copies the fields listed in the carryOverFields of the csv record
+// from the previous csv that are required after upgrade in the new csv
 func (r *ClusterServiceVersionDeploymentScaler) syncNewCsvWithPrevCsv(prevCsv *opv1a1.ClusterServiceVersion, newCsv *opv1a1.ClusterServiceVersion) {
 
+	r.odfOperatorConfigAccessMutex.Lock()
+	carryOverFields := r.odfCsvCarryOverFields[newCsv.Name]
+	r.odfOperatorConfigAccessMutex.Unlock()
+
 	prevDeployments := prevCsv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs
 	newDeployments := newCsv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs
 
@@ -143,9 +284,7 @@ func (r *ClusterServiceVersionDeploymentScaler) syncNewCsvWithPrevCsv(prevCsv *o
 			prevDeployment := &prevDeployments[j]
 
 			if newDeployment.Name == prevDeployment.Name {
-				newDeployment.Spec.Template.Spec.HostNetwork = prevDeployment.Spec.Template.Spec.HostNetwork
-				newDeployment.Spec.Template.Spec.DNSPolicy = prevDeployment.Spec.Template.Spec.DNSPolicy
-				newDeployment.Spec.Replicas = prevDeployment.Spec.Replicas
+				controllers.CarryOverDeploymentSpecFields(&prevDeployment.Spec, &newDeployment.Spec, carryOverFields)
 				break
 			}
 		}
@@ -176,6 +315,9 @@ func (r *ClusterServiceVersionDeploymentScaler) scaleDownCsvDeployments(logger l
 
 func (r *ClusterServiceVersionDeploymentScaler) isCsvManagedByOdf(csv *opv1a1.ClusterServiceVersion) bool {
 
+	r.odfOperatorConfigAccessMutex.Lock()
+	defer r.odfOperatorConfigAccessMutex.Unlock()
+
 	return r.odfOwnedCsvNames[csv.Name]
 }
 
@@ -0,0 +1,291 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/red-hat-storage/odf-operator/controllers"
+)
+
+const (
+	testOperatorNamespace = "openshift-storage"
+	testPkgsConfigMapName = "odf-operator-pkgs-config"
+	testCsvName           = "ocs-operator.v4.20.0"
+	testDeploymentKey     = "/spec/install/spec/deployments/0/spec/replicas"
+)
+
+var _ = os.Setenv("OPERATOR_NAMESPACE", testOperatorNamespace)
+var _ = os.Setenv("PKGS_CONFIG_MAP_NAME", testPkgsConfigMapName)
+
+func init() {
+	controllers.LoadEnv()
+}
+
+func newTestScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(opv1a1.AddToScheme(s))
+	utilruntime.Must(corev1.AddToScheme(s))
+	return s
+}
+
+func newTestOdfConfigMap() *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	cm.Name = testPkgsConfigMapName
+	cm.Namespace = testOperatorNamespace
+	cm.Data = map[string]string{
+		"ocs": fmt.Sprintf("csv: %s\npkg: ocs-operator\n", testCsvName),
+	}
+	return cm
+}
+
+func newTestScaleStateConfigMap(state controllers.CsvScaleState) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	cm.Name = controllers.CsvScaleStateConfigMapName
+	cm.Namespace = testOperatorNamespace
+	cm.Data = map[string]string{testCsvName: string(state)}
+	return cm
+}
+
+func newTestCsvRequest(t *testing.T, operation admissionv1.Operation, name string, replicas int32) admission.Request {
+	t.Helper()
+
+	csv := &opv1a1.ClusterServiceVersion{}
+	csv.APIVersion = opv1a1.SchemeGroupVersion.String()
+	csv.Kind = opv1a1.ClusterServiceVersionKind
+	csv.Name = name
+	csv.Namespace = testOperatorNamespace
+	csv.Spec.InstallStrategy.StrategyName = opv1a1.InstallStrategyNameDeployment
+	csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs = []opv1a1.StrategyDeploymentSpec{{
+		Name: "ocs-operator",
+		Spec: appsv1.DeploymentSpec{Replicas: ptr.To(replicas)},
+	}}
+
+	raw, err := json.Marshal(csv)
+	assert.NoError(t, err)
+
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: operation,
+		Name:      name,
+		Namespace: csv.Namespace,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+}
+
+func newTestCsvScaler(cli client.Client) *ClusterServiceVersionDeploymentScaler {
+	return &ClusterServiceVersionDeploymentScaler{
+		Client:            cli,
+		Decoder:           admission.NewDecoder(newTestScheme()),
+		OperatorNamespace: testOperatorNamespace,
+		Audit:             NewCsvAdmissionAudit(10),
+	}
+}
+
+// replicasPatch returns the value of the patch of the replicas of the csv deployment, if any
+func replicasPatch(resp admission.Response) (any, bool) {
+	for _, patch := range resp.Patches {
+		if patch.Path == testDeploymentKey {
+			return patch.Value, true
+		}
+	}
+	return nil, false
+}
+
+func TestCsvWebhookUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		state           controllers.CsvScaleState
+		replicas        int32
+		wantOutcome     string
+		wantReplicas    any
+		wantReplicasSet bool
+	}{
+		{
+			name:            "scaled up state repairs scaled down csv",
+			state:           controllers.CsvScaledUp,
+			replicas:        0,
+			wantOutcome:     admissionOutcomeRepaired,
+			wantReplicas:    json.Number("1"),
+			wantReplicasSet: true,
+		},
+		{
+			name:        "scaled up state allows scaled up csv",
+			state:       controllers.CsvScaledUp,
+			replicas:    1,
+			wantOutcome: admissionOutcomeAllowed,
+		},
+		{
+			name:            "scaled down state repairs scaled up csv",
+			state:           controllers.CsvScaledDown,
+			replicas:        1,
+			wantOutcome:     admissionOutcomeRepaired,
+			wantReplicas:    json.Number("0"),
+			wantReplicasSet: true,
+		},
+		{
+			name:        "scaled down state allows scaled down csv",
+			state:       controllers.CsvScaledDown,
+			replicas:    0,
+			wantOutcome: admissionOutcomeAllowed,
+		},
+		{
+			name:        "no recorded state allows csv",
+			replicas:    0,
+			wantOutcome: admissionOutcomeAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{newTestOdfConfigMap()}
+			if tt.state != "" {
+				objs = append(objs, newTestScaleStateConfigMap(tt.state))
+			}
+			cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objs...).Build()
+			scaler := newTestCsvScaler(cli)
+
+			resp := scaler.Handle(ctx, newTestCsvRequest(t, admissionv1.Update, testCsvName, tt.replicas))
+			assert.True(t, resp.Allowed)
+
+			value, ok := replicasPatch(resp)
+			assert.Equal(t, tt.wantReplicasSet, ok)
+			assert.Equal(t, tt.wantReplicas, value)
+
+			records := scaler.Audit.Records()
+			if assert.Len(t, records, 1) {
+				assert.Equal(t, tt.wantOutcome, records[0].Outcome)
+			}
+		})
+	}
+}
+
+func TestCsvWebhookUnmanagedCsv(t *testing.T) {
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(newTestOdfConfigMap()).Build()
+	scaler := newTestCsvScaler(cli)
+
+	resp := scaler.Handle(context.Background(), newTestCsvRequest(t, admissionv1.Create, "other-operator.v1.0.0", 1))
+	assert.True(t, resp.Allowed)
+	assert.Empty(t, resp.Patches)
+	assert.Empty(t, scaler.Audit.Records())
+}
+
+func TestCsvWebhookErrors(t *testing.T) {
+	ctx := context.Background()
+
+	failScaleStateGet := interceptor.Funcs{
+		Get: func(ctx context.Context, cli client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if key.Name == controllers.CsvScaleStateConfigMapName {
+				return fmt.Errorf("scale state unavailable")
+			}
+			return cli.Get(ctx, key, obj, opts...)
+		},
+	}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		objs        []client.Object
+		funcs       interceptor.Funcs
+		wantAllowed bool
+		wantCode    int32
+		wantOutcome string
+	}{
+		{
+			name:        "update allowed when odf configmap is missing",
+			operation:   admissionv1.Update,
+			wantAllowed: true,
+			wantOutcome: admissionOutcomeAllowedOnError,
+		},
+		{
+			name:        "update allowed when scale state can't be read",
+			operation:   admissionv1.Update,
+			objs:        []client.Object{newTestOdfConfigMap()},
+			funcs:       failScaleStateGet,
+			wantAllowed: true,
+			wantOutcome: admissionOutcomeAllowedOnError,
+		},
+		{
+			name:        "create errored when odf configmap is missing",
+			operation:   admissionv1.Create,
+			wantCode:    http.StatusInternalServerError,
+			wantOutcome: admissionOutcomeErrored,
+		},
+		{
+			name:        "create errored when scale state can't be read",
+			operation:   admissionv1.Create,
+			objs:        []client.Object{newTestOdfConfigMap()},
+			funcs:       failScaleStateGet,
+			wantCode:    http.StatusInternalServerError,
+			wantOutcome: admissionOutcomeErrored,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(tt.objs...).WithInterceptorFuncs(tt.funcs).Build()
+			scaler := newTestCsvScaler(cli)
+
+			resp := scaler.Handle(ctx, newTestCsvRequest(t, tt.operation, testCsvName, 1))
+			assert.Equal(t, tt.wantAllowed, resp.Allowed)
+			assert.Empty(t, resp.Patches)
+			if tt.wantAllowed {
+				assert.NotEmpty(t, resp.Warnings)
+			} else {
+				assert.Equal(t, tt.wantCode, resp.Result.Code)
+			}
+
+			records := scaler.Audit.Records()
+			if assert.Len(t, records, 1) {
+				assert.Equal(t, tt.wantOutcome, records[0].Outcome)
+			}
+		})
+	}
+}
+
+func TestCsvWebhookDecodeError(t *testing.T) {
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(newTestOdfConfigMap()).Build()
+	scaler := newTestCsvScaler(cli)
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		Object:    runtime.RawExtension{Raw: []byte("{not json")},
+	}}
+
+	resp := scaler.Handle(context.Background(), req)
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusBadRequest), resp.Result.Code)
+}
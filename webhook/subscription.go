@@ -0,0 +1,198 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/red-hat-storage/odf-operator/controllers"
+)
+
+// SubscriptionValidator warns about, or optionally rejects, the edits of the subscriptions
+// managed by ODF which conflict with the state desired by odf-operator. The subscriptions
+// are only warned about on creation, as OLM creates the subscriptions of the dependencies.
+type SubscriptionValidator struct {
+	client.Client
+
+	Decoder admission.Decoder
+}
+
+//+kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+func (r *SubscriptionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+
+	logger := log.FromContext(ctx)
+	logger.Info("request received for subscription review", "operation", req.Operation)
+
+	sub := &opv1a1.Subscription{}
+	if err := r.Decoder.Decode(req, sub); err != nil {
+		logger.Error(err, "failed decoding admission review as subscription")
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding admission review as subscription: %v", err))
+	}
+
+	var oldSub *opv1a1.Subscription
+	if req.Operation == admissionv1.Update {
+		oldSub = &opv1a1.Subscription{}
+		if err := r.Decoder.DecodeRaw(req.OldObject, oldSub); err != nil {
+			logger.Error(err, "failed decoding old object as subscription")
+			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding old object as subscription: %v", err))
+		}
+	}
+
+	if sub.Spec == nil {
+		return admission.Allowed("subscription has no spec")
+	}
+
+	record, err := getSubscriptionRecord(ctx, r.Client, logger, sub)
+	if err != nil {
+		// the webhook is only a guard, the subscription controller reports the configmap error
+		return admission.Allowed("odf configmap is not available")
+	}
+	if record == nil {
+		return admission.Allowed("subscription is not managed by ODF")
+	}
+
+	odfSub, err := controllers.GetOdfSubscription(ctx, r.Client)
+	if err != nil {
+		logger.Info("odf-operator subscription is not available, only checking the channel", "error", err.Error())
+	}
+
+	conflicts := controllers.GetSubscriptionConflicts(sub, oldSub, record, odfSub)
+	if len(conflicts) == 0 {
+		return admission.Allowed("subscription is in line with the desired state")
+	}
+
+	logger.Info("subscription edit conflicts with the desired state", "subscription", sub.Name, "conflicts", conflicts)
+
+	message := fmt.Sprintf("subscription %s is managed by odf-operator and will be reconciled to its desired state: %s. See %s for the supported customizations",
+		sub.Name, strings.Join(conflicts, "; "), controllers.SubscriptionCustomizationDocURL)
+
+	if req.Operation == admissionv1.Update && controllers.RejectConflictingSubscriptionEdits() {
+		return admission.Denied(message)
+	}
+
+	return admission.Allowed("subscription edit conflicts with the desired state").WithWarnings(message)
+}
+
+func (r *SubscriptionValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+
+	mgr.GetWebhookServer().Register(controllers.SubscriptionWebhookPath, &webhook.Admission{Handler: r})
+
+	return nil
+}
+
+// SubscriptionConfigDefaulter applies the config desired by ODF to the managed subscriptions on creation,
+// as the subscriptions created by OLM dependency resolution are otherwise only updated afterwards.
+type SubscriptionConfigDefaulter struct {
+	client.Client
+
+	Decoder admission.Decoder
+}
+
+func (r *SubscriptionConfigDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+
+	logger := log.FromContext(ctx)
+	logger.Info("request received for subscription config", "operation", req.Operation)
+
+	sub := &opv1a1.Subscription{}
+	if err := r.Decoder.Decode(req, sub); err != nil {
+		logger.Error(err, "failed decoding admission review as subscription")
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding admission review as subscription: %v", err))
+	}
+
+	if sub.Spec == nil {
+		return admission.Allowed("subscription has no spec")
+	}
+
+	// the subscription controller applies the same config later, do not block OLM on internal errors
+	record, err := getSubscriptionRecord(ctx, r.Client, logger, sub)
+	if err != nil {
+		logger.Error(err, "failed to get odf configmap, allowing the subscription unchanged", "subscription", sub.Name)
+		return admission.Allowed("odf configmap is not available").
+			WithWarnings(fmt.Sprintf("the config desired by ODF is not applied to subscription %s, it is applied later by odf-operator: %v", sub.Name, err))
+	}
+	if record == nil {
+		return admission.Allowed("subscription is not managed by ODF")
+	}
+
+	desiredSubscription, err := controllers.GetDesiredSubscription(ctx, r.Client, &controllers.OlmPkgRecord{
+		Channel:   record.Channel,
+		Csv:       record.Csv,
+		Pkg:       record.Pkg,
+		Namespace: record.Namespace,
+	})
+	if err != nil {
+		logger.Error(err, "failed getting desired subscription, allowing the subscription unchanged", "subscription", sub.Name)
+		return admission.Allowed("desired subscription is not available").
+			WithWarnings(fmt.Sprintf("the config desired by ODF is not applied to subscription %s, it is applied later by odf-operator: %v", sub.Name, err))
+	}
+
+	if !controllers.ApplyDesiredSubscriptionConfig(sub, desiredSubscription) {
+		return admission.Allowed("subscription is in line with the desired config")
+	}
+
+	logger.Info("applying desired config to the subscription", "subscription", sub.Name)
+
+	marshaledSub, err := json.Marshal(sub)
+	if err != nil {
+		logger.Error(err, "failed marshaling subscription")
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed marshaling subscription: %v", err))
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledSub)
+}
+
+func (r *SubscriptionConfigDefaulter) SetupWebhookWithManager(mgr ctrl.Manager) error {
+
+	mgr.GetWebhookServer().Register(controllers.SubscriptionConfigWebhookPath, &webhook.Admission{Handler: r})
+
+	return nil
+}
+
+// getSubscriptionRecord returns the pkgs-config record of the subscription, or nil if it is not managed by ODF
+func getSubscriptionRecord(ctx context.Context, cli client.Client, logger logr.Logger, sub *opv1a1.Subscription) (*controllers.OdfOperatorConfigMapRecord, error) {
+
+	configmap, err := controllers.GetOdfConfigMap(ctx, cli, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *controllers.OdfOperatorConfigMapRecord
+	controllers.ParseOdfConfigMapRecords(logger, configmap, func(rec *controllers.OdfOperatorConfigMapRecord, key, rawValue string) {
+		if rec.Pkg == sub.Spec.Package && rec.Namespace == sub.Namespace {
+			// the record is reused by the parser, keep a copy
+			matched := *rec
+			record = &matched
+		}
+	})
+
+	return record, nil
+}
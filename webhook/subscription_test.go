@@ -0,0 +1,286 @@
+/*
+Copyright 2026 Red Hat OpenShift Data Foundation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/red-hat-storage/odf-operator/controllers"
+)
+
+var testToleration = corev1.Toleration{
+	Key:      "node.ocs.openshift.io/storage",
+	Operator: corev1.TolerationOpEqual,
+	Value:    "true",
+	Effect:   corev1.TaintEffectNoSchedule,
+}
+
+func newTestSubscriptionOdfConfigMap() *corev1.ConfigMap {
+	cm := newTestOdfConfigMap()
+	cm.Data = map[string]string{
+		"ocs": "channel: stable-4.20\ncsv: ocs-operator.v4.20.0\npkg: ocs-operator\n",
+	}
+	return cm
+}
+
+func newTestOdfSubscription() *opv1a1.Subscription {
+	sub := newTestSubscription("odf-operator", "odf-operator", "stable-4.20")
+	sub.Spec.CatalogSource = "redhat-operators"
+	sub.Spec.CatalogSourceNamespace = "openshift-marketplace"
+	sub.Spec.Config = &opv1a1.SubscriptionConfig{Tolerations: []corev1.Toleration{testToleration}}
+	return sub
+}
+
+func newTestSubscription(name, pkg, channel string) *opv1a1.Subscription {
+	sub := &opv1a1.Subscription{}
+	sub.APIVersion = opv1a1.SchemeGroupVersion.String()
+	sub.Kind = opv1a1.SubscriptionKind
+	sub.Name = name
+	sub.Namespace = testOperatorNamespace
+	sub.Spec = &opv1a1.SubscriptionSpec{
+		Package: pkg,
+		Channel: channel,
+	}
+	return sub
+}
+
+func newTestSubscriptionRequest(t *testing.T, operation admissionv1.Operation, sub, oldSub *opv1a1.Subscription) admission.Request {
+	t.Helper()
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: operation,
+		Name:      sub.Name,
+		Namespace: sub.Namespace,
+	}}
+
+	raw, err := json.Marshal(sub)
+	assert.NoError(t, err)
+	req.Object = runtime.RawExtension{Raw: raw}
+
+	if oldSub != nil {
+		raw, err = json.Marshal(oldSub)
+		assert.NoError(t, err)
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+
+	return req
+}
+
+func TestSubscriptionValidator(t *testing.T) {
+	ctx := context.Background()
+
+	ocsSub := newTestSubscription("ocs-operator", "ocs-operator", "stable-4.20")
+	editedSub := ocsSub.DeepCopy()
+	editedSub.Spec.Channel = "stable-4.19"
+	otherSub := newTestSubscription("other-operator", "other-operator", "alpha")
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		sub         *opv1a1.Subscription
+		oldSub      *opv1a1.Subscription
+		reject      string
+		wantAllowed bool
+		wantWarning bool
+	}{
+		{
+			name:        "edit in line with the desired state is allowed",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         ocsSub,
+			oldSub:      ocsSub,
+			wantAllowed: true,
+		},
+		{
+			name:        "conflicting edit is allowed with a warning",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         editedSub,
+			oldSub:      ocsSub,
+			wantAllowed: true,
+			wantWarning: true,
+		},
+		{
+			name:        "conflicting edit is allowed with a warning when rejection is disabled",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         editedSub,
+			oldSub:      ocsSub,
+			reject:      "false",
+			wantAllowed: true,
+			wantWarning: true,
+		},
+		{
+			name:   "conflicting edit is rejected when rejection is enabled",
+			objs:   []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:    editedSub,
+			oldSub: ocsSub,
+			reject: "true",
+		},
+		{
+			name:        "conflicting subscription is allowed with a warning on creation when rejection is enabled",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         editedSub,
+			reject:      "true",
+			wantAllowed: true,
+			wantWarning: true,
+		},
+		{
+			name:        "conflicting edit is checked without the odf subscription",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap()},
+			sub:         editedSub,
+			oldSub:      ocsSub,
+			reject:      "true",
+			wantAllowed: false,
+		},
+		{
+			name:        "subscription not managed by ODF is allowed",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         otherSub,
+			reject:      "true",
+			wantAllowed: true,
+		},
+		{
+			name:        "missing odf configmap allows the subscription",
+			sub:         editedSub,
+			oldSub:      ocsSub,
+			reject:      "true",
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("REJECT_CONFLICTING_SUBSCRIPTION_EDITS", tt.reject)
+
+			cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(tt.objs...).Build()
+			validator := &SubscriptionValidator{Client: cli, Decoder: admission.NewDecoder(newTestScheme())}
+
+			operation := admissionv1.Create
+			if tt.oldSub != nil {
+				operation = admissionv1.Update
+			}
+
+			resp := validator.Handle(ctx, newTestSubscriptionRequest(t, operation, tt.sub, tt.oldSub))
+			assert.Equal(t, tt.wantAllowed, resp.Allowed)
+			assert.Equal(t, tt.wantWarning, len(resp.Warnings) > 0)
+			if !tt.wantAllowed {
+				assert.Equal(t, int32(http.StatusForbidden), resp.Result.Code)
+				assert.Contains(t, resp.Result.Message, controllers.SubscriptionCustomizationDocURL)
+			}
+		})
+	}
+}
+
+func TestSubscriptionValidatorDecodeError(t *testing.T) {
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	validator := &SubscriptionValidator{Client: cli, Decoder: admission.NewDecoder(newTestScheme())}
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte("{not json")},
+	}}
+
+	resp := validator.Handle(context.Background(), req)
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusBadRequest), resp.Result.Code)
+}
+
+func TestSubscriptionConfigDefaulter(t *testing.T) {
+	ctx := context.Background()
+
+	configuredSub := newTestSubscription("ocs-operator", "ocs-operator", "stable-4.20")
+	configuredSub.Spec.Config = &opv1a1.SubscriptionConfig{Tolerations: []corev1.Toleration{testToleration}}
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		sub         *opv1a1.Subscription
+		wantAllowed bool
+		wantPatched bool
+		wantWarning bool
+	}{
+		{
+			name:        "desired config is applied",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         newTestSubscription("ocs-operator", "ocs-operator", "stable-4.20"),
+			wantAllowed: true,
+			wantPatched: true,
+		},
+		{
+			name:        "subscription in line with the desired config is allowed",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         configuredSub,
+			wantAllowed: true,
+		},
+		{
+			name:        "subscription not managed by ODF is allowed",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap(), newTestOdfSubscription()},
+			sub:         newTestSubscription("other-operator", "other-operator", "alpha"),
+			wantAllowed: true,
+		},
+		{
+			name:        "missing odf configmap allows the subscription with a warning",
+			objs:        []client.Object{newTestOdfSubscription()},
+			sub:         newTestSubscription("ocs-operator", "ocs-operator", "stable-4.20"),
+			wantAllowed: true,
+			wantWarning: true,
+		},
+		{
+			name:        "missing odf subscription allows the subscription with a warning",
+			objs:        []client.Object{newTestSubscriptionOdfConfigMap()},
+			sub:         newTestSubscription("ocs-operator", "ocs-operator", "stable-4.20"),
+			wantAllowed: true,
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(tt.objs...).Build()
+			defaulter := &SubscriptionConfigDefaulter{Client: cli, Decoder: admission.NewDecoder(newTestScheme())}
+
+			resp := defaulter.Handle(ctx, newTestSubscriptionRequest(t, admissionv1.Create, tt.sub, nil))
+			assert.Equal(t, tt.wantAllowed, resp.Allowed)
+			assert.Equal(t, tt.wantPatched, len(resp.Patches) > 0)
+			assert.Equal(t, tt.wantWarning, len(resp.Warnings) > 0)
+		})
+	}
+}
+
+func TestSubscriptionConfigDefaulterDecodeError(t *testing.T) {
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme()).Build()
+	defaulter := &SubscriptionConfigDefaulter{Client: cli, Decoder: admission.NewDecoder(newTestScheme())}
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte("{not json")},
+	}}
+
+	resp := defaulter.Handle(context.Background(), req)
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusBadRequest), resp.Result.Code)
+}